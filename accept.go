@@ -0,0 +1,129 @@
+package httpserve
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// parseAccept will parse the media ranges of an Accept header, invalid ranges are ignored
+func parseAccept(header string) (ranges []acceptRange) {
+	for _, part := range strings.Split(header, ",") {
+		if part = strings.TrimSpace(part); len(part) == 0 {
+			continue
+		}
+
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		var (
+			ar acceptRange
+			ok bool
+		)
+
+		if ar.typ, ar.subtype, ok = strings.Cut(mediaType, "/"); !ok {
+			continue
+		}
+
+		ar.q = 1
+		if q, ok := params["q"]; ok {
+			if ar.q, err = strconv.ParseFloat(q, 64); err != nil || ar.q < 0 || ar.q > 1 {
+				continue
+			}
+
+			delete(params, "q")
+		}
+
+		ar.params = len(params)
+		ranges = append(ranges, ar)
+	}
+
+	return
+}
+
+// acceptRange represents a single media range of an Accept header
+type acceptRange struct {
+	typ     string
+	subtype string
+	q       float64
+	// params is the number of media type parameters, used for precedence
+	params int
+}
+
+// specificity will return the precedence of the range when matching, higher values are more specific
+func (a *acceptRange) specificity() int {
+	switch {
+	case a.typ == "*":
+		return 0
+	case a.subtype == "*":
+		return 1
+	default:
+		return 2 + a.params
+	}
+}
+
+func (a *acceptRange) matches(typ, subtype string) bool {
+	if a.typ != "*" && a.typ != typ {
+		return false
+	}
+
+	return a.subtype == "*" || a.subtype == subtype
+}
+
+type acceptRanges []acceptRange
+
+// quality will return the quality value of a media type, using the most specific matching range
+func (a acceptRanges) quality(mediaType string) (q float64) {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	best := -1
+	for _, ar := range a {
+		if !ar.matches(typ, subtype) {
+			continue
+		}
+
+		if s := ar.specificity(); s > best {
+			best = s
+			q = ar.q
+		}
+	}
+
+	return
+}
+
+// explicit will return whether or not the media type is explicitly accepted (not through a wildcard)
+func (a acceptRanges) explicit(mediaType string) bool {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	for _, ar := range a {
+		if ar.typ == typ && ar.subtype == subtype {
+			return ar.q > 0
+		}
+	}
+
+	return false
+}
+
+// negotiate will return the media type with the highest quality value, ties are won by the earliest media type
+func negotiate(accept string, mediaTypes []string) (mediaType string, ok bool) {
+	if len(mediaTypes) == 0 {
+		return
+	}
+
+	if accept = strings.TrimSpace(accept); len(accept) == 0 {
+		// No Accept header means any media type is acceptable
+		return mediaTypes[0], true
+	}
+
+	ranges := acceptRanges(parseAccept(accept))
+	var best float64
+	for _, mt := range mediaTypes {
+		if q := ranges.quality(mt); q > best {
+			best = q
+			mediaType = mt
+		}
+	}
+
+	ok = best > 0
+	return
+}
@@ -0,0 +1,33 @@
+package httpserve
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	mediaTypes := []string{"application/json", "application/xml", "text/xml"}
+	tcs := []struct {
+		accept    string
+		mediaType string
+		ok        bool
+	}{
+		{accept: "", mediaType: "application/json", ok: true},
+		{accept: "*/*", mediaType: "application/json", ok: true},
+		{accept: "application/xml", mediaType: "application/xml", ok: true},
+		{accept: "text/*", mediaType: "text/xml", ok: true},
+		{accept: "application/json;q=0.5, application/xml", mediaType: "application/xml", ok: true},
+		{accept: "application/*;q=0.2, application/json;q=0", mediaType: "application/xml", ok: true},
+		{accept: "*/*;q=0.1, text/xml", mediaType: "text/xml", ok: true},
+		{accept: "text/html", ok: false},
+		{accept: "application/json;q=0", ok: false},
+	}
+
+	for _, tc := range tcs {
+		mediaType, ok := negotiate(tc.accept, mediaTypes)
+		if ok != tc.ok {
+			t.Fatalf("invalid ok value for \"%s\", expected %v and received %v", tc.accept, tc.ok, ok)
+		}
+
+		if mediaType != tc.mediaType {
+			t.Fatalf("invalid media type for \"%s\", expected \"%s\" and received \"%s\"", tc.accept, tc.mediaType, mediaType)
+		}
+	}
+}
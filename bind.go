@@ -0,0 +1,233 @@
+package httpserve
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"github.com/gdbu/reflectio"
+	"github.com/vroomy/httpserve/form"
+)
+
+// bindSources are the request sources used by Context.BindRequest, in order of increasing precedence.
+// Note: Each source has its own cache as reflectio caches by type regardless of the tag key.
+var bindSources = [...]bindSource{
+	{tag: "query", cache: reflectio.NewCache(), lookup: lookupQuery},
+	{tag: "header", cache: reflectio.NewCache(), lookup: lookupHeader},
+	{tag: "cookie", cache: reflectio.NewCache(), lookup: lookupCookie},
+	{tag: "param", cache: reflectio.NewCache(), lookup: lookupParam},
+}
+
+// BindRequest will bind the request body, path parameters, query, headers and cookies to the provided struct pointer
+//
+// Fields are bound using the param, query, header and cookie struct tags. The
+// body (when present) is bound first using Bind, followed by the query,
+// headers, cookies and path parameters. A default value can be provided with
+// the default struct tag, it is set before binding so it is only kept when the
+// field is not present in the body or request. All conversion errors are
+// returned as FieldErrors. When validation on bind is enabled, the bound value
+// is validated.
+func (c *Context) BindRequest(value interface{}) (err error) {
+	rval := reflect.ValueOf(value)
+	if rval.Kind() != reflect.Ptr || rval.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindValue
+	}
+
+	// Defaults are set first, so any source providing the field (including the body) takes precedence
+	rval = rval.Elem()
+	errs := setBindDefaults(rval, value)
+	if c.hasBody() {
+		if err = c.bind(value); err != nil {
+			return
+		}
+	}
+
+	var br requestBinder
+	br.c = c
+	for _, src := range bindSources {
+		for _, entry := range sortedEntries(src.cache.Get(value, src.tag)) {
+			raw, ok := src.lookup(&br, entry.key)
+			if !ok {
+				continue
+			}
+
+			if err = form.SetValueAsString(rval.Field(entry.index), raw); err != nil {
+				errs = append(errs, &FieldError{Source: src.tag, Field: entry.key, Value: raw, Code: "invalid", Err: err})
+			}
+		}
+	}
+
+	if err = errs.err(); err != nil {
+		return
+	}
+
+	return c.autoValidate(value)
+}
+
+// BindQuery will bind the request query to the provided value using the form struct tags
+//
+// The query is decoded using the form decoder, so repeated keys are bound to
+// slices and nested keys (filter[status], filter.status) are bound to nested
+// structs and maps. Conversion errors are returned as FieldErrors.
+func (c *Context) BindQuery(value interface{}) (err error) {
+	if err = c.bindQuery(value); err != nil {
+		return
+	}
+
+	return c.autoValidate(value)
+}
+
+// BindQueryAndForm will bind the request query and form body to the provided value using the form struct tags
+//
+// The query is bound first, followed by the body (either URL encoded or
+// multipart, ErrUnsupportedMediaType is returned for other bodies). Values
+// within the body take precedence over the query for single value fields,
+// slice fields contain the query values followed by the body values.
+// Conversion errors from both sources are returned together as FieldErrors.
+func (c *Context) BindQueryAndForm(value interface{}) (err error) {
+	var errs FieldErrors
+	if err = appendFieldErrors(&errs, c.bindQuery(value)); err != nil {
+		return
+	}
+
+	if c.hasBody() {
+		switch mediaType, _, _ := mime.ParseMediaType(c.request.Header.Get("Content-Type")); mediaType {
+		case formContentType, "":
+			err = c.bindForm(value)
+		case multipartContentType:
+			err = c.bindMultipart(value, nil)
+		default:
+			return ErrUnsupportedMediaType
+		}
+
+		if err = appendFieldErrors(&errs, err); err != nil {
+			return
+		}
+	}
+
+	if err = errs.err(); err != nil {
+		return
+	}
+
+	return c.autoValidate(value)
+}
+
+func (c *Context) bindQuery(value interface{}) (err error) {
+	return formFieldErrors("query", form.Unmarshal(c.request.URL.RawQuery, value))
+}
+
+// appendFieldErrors will append field errors to the list, other errors are returned as-is
+func appendFieldErrors(errs *FieldErrors, err error) error {
+	var ferrs FieldErrors
+	if !errors.As(err, &ferrs) {
+		return err
+	}
+
+	*errs = append(*errs, ferrs...)
+	return nil
+}
+
+func (c *Context) hasBody() bool {
+	body := c.request.Body
+	return body != nil && body != http.NoBody && c.request.ContentLength != 0
+}
+
+// setBindDefaults will set the default struct tag value of each field bound by a request source
+func setBindDefaults(rval reflect.Value, value interface{}) (errs FieldErrors) {
+	rtype := rval.Type()
+	set := make(map[int]bool)
+	for _, src := range bindSources {
+		for _, entry := range sortedEntries(src.cache.Get(value, src.tag)) {
+			raw, ok := rtype.Field(entry.index).Tag.Lookup("default")
+			if !ok || set[entry.index] {
+				continue
+			}
+
+			set[entry.index] = true
+			if err := form.SetValueAsString(rval.Field(entry.index), raw); err != nil {
+				errs = append(errs, &FieldError{Source: src.tag, Field: entry.key, Value: raw, Code: "invalid", Err: err})
+			}
+		}
+	}
+
+	return
+}
+
+type bindSource struct {
+	tag    string
+	cache  *reflectio.Cache
+	lookup func(br *requestBinder, key string) (value string, ok bool)
+}
+
+// requestBinder holds the lazily parsed request values during Context.BindRequest
+type requestBinder struct {
+	c     *Context
+	query url.Values
+}
+
+type bindEntry struct {
+	key   string
+	index int
+}
+
+// sortedEntries will return the entries of a reflectio.Map sorted by field index
+func sortedEntries(m reflectio.Map) (entries []bindEntry) {
+	entries = make([]bindEntry, 0, len(m))
+	for key, field := range m {
+		if key == "-" {
+			continue
+		}
+
+		entries = append(entries, bindEntry{key: key, index: field.FieldIndex()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].index < entries[j].index
+	})
+
+	return
+}
+
+func lookupQuery(br *requestBinder, key string) (value string, ok bool) {
+	if br.query == nil {
+		br.query = br.c.request.URL.Query()
+	}
+
+	var vs []string
+	if vs, ok = br.query[key]; !ok || len(vs) == 0 {
+		return "", false
+	}
+
+	return vs[0], true
+}
+
+func lookupHeader(br *requestBinder, key string) (value string, ok bool) {
+	vs := br.c.request.Header.Values(key)
+	if len(vs) == 0 {
+		return
+	}
+
+	return vs[0], true
+}
+
+func lookupCookie(br *requestBinder, key string) (value string, ok bool) {
+	cookie, err := br.c.request.Cookie(key)
+	if err != nil {
+		return
+	}
+
+	return cookie.Value, true
+}
+
+func lookupParam(br *requestBinder, key string) (value string, ok bool) {
+	for _, p := range br.c.Params {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+
+	return
+}
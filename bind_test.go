@@ -0,0 +1,176 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_BindRequest(t *testing.T) {
+	type testRequest struct {
+		ID      string `param:"id"`
+		Page    int    `query:"page" default:"1"`
+		Limit   uint16 `query:"limit" default:"25"`
+		Tenant  string `header:"X-Tenant"`
+		Session string `cookie:"sid"`
+		Name    string `json:"name"`
+	}
+
+	req := httptest.NewRequest("PUT", "/users/42?limit=10", strings.NewReader(`{"name":"John Doe"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant", "acme")
+	req.AddCookie(&http.Cookie{Name: "sid", Value: "abc"})
+	ctx := newContext(httptest.NewRecorder(), req, Params{{Key: "id", Value: "42"}})
+
+	var tr testRequest
+	if err := ctx.BindRequest(&tr); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := testRequest{ID: "42", Page: 1, Limit: 10, Tenant: "acme", Session: "abc", Name: "John Doe"}
+	if tr != expected {
+		t.Fatalf("invalid value, expected %#v and received %#v", expected, tr)
+	}
+}
+
+func TestContext_BindRequest_body_default(t *testing.T) {
+	type testRequest struct {
+		Page  int `query:"page" form:"page" default:"1"`
+		Limit int `query:"limit" form:"limit" default:"25"`
+	}
+
+	req := httptest.NewRequest("POST", "/users?limit=10", strings.NewReader("page=5"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	var tr testRequest
+	if err := ctx.BindRequest(&tr); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := testRequest{Page: 5, Limit: 10}
+	if tr != expected {
+		t.Fatalf("invalid value, expected %#v and received %#v", expected, tr)
+	}
+}
+
+func TestContext_BindRequest_errors(t *testing.T) {
+	type testRequest struct {
+		Page  int  `query:"page"`
+		Limit int  `query:"limit"`
+		Debug bool `header:"X-Debug"`
+	}
+
+	req := httptest.NewRequest("GET", "/users?page=two&limit=-1", nil)
+	req.Header.Set("X-Debug", "maybe")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	var tr testRequest
+	err := ctx.BindRequest(&tr)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected FieldErrors and received %#v", err)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("invalid number of errors, expected %d and received %d", 2, len(errs))
+	}
+
+	if errs[0].Field != "page" || errs[0].Value != "two" || errs[1].Field != "X-Debug" {
+		t.Fatalf("invalid errors, received %v", errs)
+	}
+
+	if tr.Limit != -1 {
+		t.Fatalf("invalid limit, expected %d and received %d", -1, tr.Limit)
+	}
+}
+
+func TestContext_BindForm_errors(t *testing.T) {
+	type testForm struct {
+		Name string `form:"name"`
+		Age  int    `form:"age"`
+	}
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader("name=foo&age=abc"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tf testForm
+	err := newContext(httptest.NewRecorder(), req, nil).BindForm(&tf)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected FieldErrors, received %v", err)
+	}
+
+	if len(errs) != 1 || errs[0].Source != "body" || errs[0].Field != "age" || errs[0].Value != "abc" || errs[0].Code != "invalid" {
+		t.Fatalf("invalid field errors: %#v", errs)
+	}
+}
+
+func TestContext_BindQuery(t *testing.T) {
+	type testFilters struct {
+		Status []string `form:"status"`
+		Sort   string   `form:"sort"`
+		Filter struct {
+			Owner string `form:"owner"`
+			Min   int    `form:"min"`
+		} `form:"filter"`
+	}
+
+	req := httptest.NewRequest("GET", "/?status=open&status=closed&sort=name&filter[owner]=joe&filter.min=3", nil)
+
+	var tf testFilters
+	if err := newContext(httptest.NewRecorder(), req, nil).BindQuery(&tf); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tf.Status) != 2 || tf.Status[1] != "closed" || tf.Sort != "name" || tf.Filter.Owner != "joe" || tf.Filter.Min != 3 {
+		t.Fatalf("invalid value: %+v", tf)
+	}
+
+	req = httptest.NewRequest("GET", "/?filter[min]=abc", nil)
+	err := newContext(httptest.NewRecorder(), req, nil).BindQuery(&tf)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Source != "query" || errs[0].Field != "filter[min]" {
+		t.Fatalf("expected a query field error, received %v", err)
+	}
+}
+
+func TestContext_BindQueryAndForm(t *testing.T) {
+	type testForm struct {
+		Name string   `form:"name"`
+		Page int      `form:"page"`
+		Tags []string `form:"tags"`
+	}
+
+	req := httptest.NewRequest("POST", "/?name=query&page=2&tags=a", strings.NewReader("name=body&tags=b"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tf testForm
+	if err := newContext(httptest.NewRecorder(), req, nil).BindQueryAndForm(&tf); err != nil {
+		t.Fatal(err)
+	}
+
+	if tf.Name != "body" || tf.Page != 2 || len(tf.Tags) != 2 || tf.Tags[0] != "a" || tf.Tags[1] != "b" {
+		t.Fatalf("invalid value: %+v", tf)
+	}
+
+	req = httptest.NewRequest("POST", "/?page=abc", strings.NewReader("page=def"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err := newContext(httptest.NewRecorder(), req, nil).BindQueryAndForm(&tf)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Source != "query" || errs[1].Source != "body" {
+		t.Fatalf("expected query and body field errors, received %v", err)
+	}
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"foo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err = newContext(httptest.NewRecorder(), req, nil).BindQueryAndForm(&tf); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Fatalf("expected ErrUnsupportedMediaType, received %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package httpserve
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// defaultBodyMemoryLimit is the default size at which buffered request bodies are spilled to a temporary file
+const defaultBodyMemoryLimit = 1 << 20
+
+// MaxBodySize will return a Handler which sets the maximum request body size for the route, zero is unlimited
+//
+// Note: This must precede any handlers which read the request body.
+func MaxBodySize(n int64) Handler {
+	return func(ctx *Context) {
+		ctx.maxBodySize = n
+	}
+}
+
+// Body will return a reader of the request body, the body is buffered on the first call
+//
+// Each call returns a new reader positioned at the start of the body, and
+// bind helpers called after Body will read from the buffered body. The
+// request's Body is replaced with a reader of the buffered body. Bodies
+// which exceed the body memory limit are buffered to a temporary file.
+// ErrRequestEntityTooLarge is returned when the body exceeds the maximum
+// body size.
+func (c *Context) Body() (r io.ReadSeeker, err error) {
+	if c.body == nil {
+		if err = c.bufferBody(); err != nil {
+			return
+		}
+	}
+
+	return c.body.reader(), nil
+}
+
+func (c *Context) bufferBody() (err error) {
+	var rdr io.Reader
+	if rdr, err = c.rawBody(); err != nil {
+		return
+	}
+	defer c.request.Body.Close()
+
+	limit := int64(defaultBodyMemoryLimit)
+	if c.router != nil && c.router.bodyMemoryLimit > 0 {
+		limit = c.router.bodyMemoryLimit
+	}
+
+	var b bufferedBody
+	if err = b.readFrom(rdr, limit); err != nil {
+		b.close()
+		return
+	}
+
+	c.body = &b
+	// Replace the consumed request body, so it can still be read directly from the request
+	c.request.Body = io.NopCloser(b.reader())
+	return
+}
+
+// bodyReader will return the reader used by the bind helpers, the buffered body is used when available
+func (c *Context) bodyReader() (r io.Reader, err error) {
+	if c.body != nil {
+		return c.body.reader(), nil
+	}
+
+	return c.rawBody()
+}
+
+// rawBody will return the request body limited to the maximum body size
+func (c *Context) rawBody() (r io.Reader, err error) {
+	if c.maxBodySize <= 0 {
+		return c.request.Body, nil
+	}
+
+	if c.request.ContentLength > c.maxBodySize {
+		return nil, ErrRequestEntityTooLarge
+	}
+
+	return &limitedReader{r: c.request.Body, n: c.maxBodySize}, nil
+}
+
+// limitedReader reads up to n bytes, returning ErrRequestEntityTooLarge when the underlying reader has more data
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (n int, err error) {
+	if l.n < 0 {
+		return 0, ErrRequestEntityTooLarge
+	}
+
+	// Allow reading one byte past the limit to determine if the body exceeds it
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err = l.r.Read(p)
+	if l.n -= int64(n); l.n < 0 {
+		return n + int(l.n), ErrRequestEntityTooLarge
+	}
+
+	return
+}
+
+// bufferedBody is a request body held in memory, or within a temporary file when exceeding the memory limit
+type bufferedBody struct {
+	mem  []byte
+	file *os.File
+	size int64
+}
+
+func (b *bufferedBody) readFrom(r io.Reader, memoryLimit int64) (err error) {
+	var buf bytes.Buffer
+	var n int64
+	if n, err = io.CopyN(&buf, r, memoryLimit+1); err == io.EOF {
+		// Body fits within memory limit
+		b.mem = buf.Bytes()
+		b.size = n
+		return nil
+	} else if err != nil {
+		return
+	}
+
+	// Body exceeds memory limit, spill to a temporary file
+	if b.file, err = os.CreateTemp("", "httpserve-body-*"); err != nil {
+		return
+	}
+
+	if _, err = buf.WriteTo(b.file); err != nil {
+		return
+	}
+
+	var rest int64
+	if rest, err = io.Copy(b.file, r); err != nil {
+		return
+	}
+
+	b.size = n + rest
+	return
+}
+
+func (b *bufferedBody) reader() io.ReadSeeker {
+	if b.file != nil {
+		return io.NewSectionReader(b.file, 0, b.size)
+	}
+
+	return bytes.NewReader(b.mem)
+}
+
+func (b *bufferedBody) close() {
+	if b.file == nil {
+		return
+	}
+
+	b.file.Close()
+	os.Remove(b.file.Name())
+	b.file = nil
+}
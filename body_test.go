@@ -0,0 +1,93 @@
+package httpserve
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_Body(t *testing.T) {
+	body := `{"name":"John Doe","age":33}`
+	r := newRouter()
+	r.SetBodyMemoryLimit(8)
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := acquireContext(httptest.NewRecorder(), req)
+	ctx.router = r
+	defer releaseContext(ctx)
+
+	for i := 0; i < 2; i++ {
+		rdr, err := ctx.Body()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bs, err := io.ReadAll(rdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(bs) != body {
+			t.Fatalf("invalid body, expected %q and received %q", body, string(bs))
+		}
+	}
+
+	if ctx.body.file == nil {
+		t.Fatal("expected body to be buffered to a temporary file")
+	}
+
+	var ts TestJSONStruct
+	if err := ctx.Bind(&ts); err != nil {
+		t.Fatal(err)
+	}
+
+	if ts.Name != "John Doe" || ts.Age != 33 {
+		t.Fatalf("invalid value, received %#v", ts)
+	}
+
+	// The request body remains readable after being buffered
+	bs, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(bs) != body {
+		t.Fatalf("invalid request body, expected %q and received %q", body, string(bs))
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	tcs := []struct {
+		name string
+		bind func(ctx *Context) error
+	}{
+		{name: "Bind", bind: func(ctx *Context) error { return ctx.Bind(&TestJSONStruct{}) }},
+		{name: "BindJSON", bind: func(ctx *Context) error { return ctx.BindJSON(&TestJSONStruct{}) }},
+		{name: "BindForm", bind: func(ctx *Context) error { return ctx.BindForm(&TestJSONStruct{}) }},
+		{name: "BindRequest", bind: func(ctx *Context) error { return ctx.BindRequest(&TestJSONStruct{}) }},
+		{name: "Body", bind: func(ctx *Context) (err error) {
+			_, err = ctx.Body()
+			return
+		}},
+	}
+
+	for _, tc := range tcs {
+		// Hide the content length to ensure the limit is enforced while reading
+		req := httptest.NewRequest("POST", "/", io.MultiReader(strings.NewReader(`{"name":"John Doe","age":33}`)))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := newContext(httptest.NewRecorder(), req, nil)
+		MaxBodySize(16)(ctx)
+
+		err := tc.bind(ctx)
+		if !errors.Is(err, ErrRequestEntityTooLarge) {
+			t.Fatalf("%s: invalid error, expected %v and received %v", tc.name, ErrRequestEntityTooLarge, err)
+		}
+
+		if code := ErrorStatusCode(err, 400); code != 413 {
+			t.Fatalf("%s: invalid status code, expected %d and received %d", tc.name, 413, code)
+		}
+	}
+}
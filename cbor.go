@@ -0,0 +1,118 @@
+package httpserve
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const cborContentType = "application/cbor"
+
+var _ Codec = &cborCodec{}
+
+// cborAPI is the CBOR engine used throughout the package, configured alongside the JSON engine
+var cborAPI = newCBOREngine(JSONConfig{})
+
+func newCBOREngine(cfg JSONConfig) *cborEngine {
+	encOpts := cbor.EncOptions{
+		// Times are encoded as RFC 3339 strings, matching their JSON representation
+		Time: cbor.TimeRFC3339Nano,
+	}
+
+	if cfg.SortMapKeys {
+		encOpts.Sort = cbor.SortBytewiseLexical
+	}
+
+	decOpts := cbor.DecOptions{
+		// Maps are decoded into an interface{} as map[string]interface{}, matching JSON objects
+		DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+	}
+
+	if cfg.DisallowUnknownFields {
+		decOpts.ExtraReturnErrors = cbor.ExtraDecErrorUnknownField
+	}
+
+	var (
+		c   cborEngine
+		err error
+	)
+
+	if c.enc, err = encOpts.EncMode(); err != nil {
+		panic(err)
+	}
+
+	if c.dec, err = decOpts.DecMode(); err != nil {
+		panic(err)
+	}
+
+	return &c
+}
+
+// cborEngine encodes and decodes CBOR
+//
+// Struct fields are named by their cbor tag, falling back to their json tag,
+// so values are represented consistently with the JSON engine.
+type cborEngine struct {
+	enc cbor.EncMode
+	dec cbor.DecMode
+}
+
+func (c *cborEngine) Marshal(value interface{}) ([]byte, error) {
+	return c.enc.Marshal(value)
+}
+
+func (c *cborEngine) Unmarshal(bs []byte, value interface{}) error {
+	return c.dec.Unmarshal(bs, value)
+}
+
+func (c *cborEngine) Encode(w io.Writer, value interface{}) error {
+	return c.enc.NewEncoder(w).Encode(value)
+}
+
+func (c *cborEngine) Decode(r io.Reader, value interface{}) error {
+	return c.dec.NewDecoder(r).Decode(value)
+}
+
+type cborCodec struct{}
+
+func (c *cborCodec) Encode(w io.Writer, value interface{}) error {
+	return cborAPI.Encode(w, value)
+}
+
+func (c *cborCodec) Decode(r io.Reader, value interface{}) error {
+	return cborAPI.Decode(r, value)
+}
+
+// MarshalCBOR will marshal the JSON value as CBOR, errors are marshaled as structured Error objects
+func (j JSONValue) MarshalCBOR() (bs []byte, err error) {
+	return cborAPI.Marshal(j.encoded())
+}
+
+// UnmarshalCBOR will unmarshal the JSON value from CBOR, errors are unmarshaled as *Error values
+//
+// When Data is set to a pointer prior to unmarshaling, the data will be unmarshaled into it.
+func (j *JSONValue) UnmarshalCBOR(bs []byte) (err error) {
+	var in struct {
+		Data   cbor.RawMessage `json:"data"`
+		Errors []*Error        `json:"errors"`
+		Meta   Meta            `json:"meta"`
+	}
+
+	if err = cborAPI.Unmarshal(bs, &in); err != nil {
+		return
+	}
+
+	if len(in.Data) > 0 && !bytes.Equal(in.Data, cborNull) {
+		if err = j.decodeData(cborAPI.Unmarshal, in.Data); err != nil {
+			return
+		}
+	}
+
+	j.setDecoded(in.Errors, in.Meta)
+	return
+}
+
+// cborNull is the CBOR encoding of null
+var cborNull = []byte{0xf6}
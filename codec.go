@@ -0,0 +1,189 @@
+package httpserve
+
+import (
+	"encoding/xml"
+	"io"
+	"mime"
+	"reflect"
+
+	"github.com/vroomy/httpserve/form"
+)
+
+const (
+	jsonContentType = "application/json"
+	xmlContentType  = "application/xml"
+)
+
+var (
+	_ Codec = &jsonCodec{}
+	_ Codec = &xmlCodec{}
+	_ Codec = &formCodec{}
+)
+
+// Codec encodes and decodes values for a media type
+type Codec interface {
+	Encode(w io.Writer, value interface{}) error
+	Decode(r io.Reader, value interface{}) error
+}
+
+// NewCodecs will return a new codec registry containing the built-in JSON, XML, form, MessagePack and CBOR codecs
+func NewCodecs() *Codecs {
+	var c Codecs
+	c.Set(jsonContentType, &jsonCodec{})
+	c.Set(xmlContentType, &xmlCodec{})
+	c.Set("text/xml", &xmlCodec{})
+	c.Set(formContentType, &formCodec{})
+	c.Set(msgpackContentType, &msgpackCodec{})
+	c.Set(cborContentType, &cborCodec{})
+	return &c
+}
+
+// Codecs is a registry of codecs by media type
+//
+// The order in which media types are registered determines the server's
+// preference when negotiating a response representation.
+type Codecs struct {
+	mediaTypes []string
+	m          map[string]Codec
+}
+
+// Set will set the codec for a media type, a nil codec will remove the media type
+func (c *Codecs) Set(mediaType string, codec Codec) {
+	if c.m == nil {
+		c.m = make(map[string]Codec)
+	}
+
+	if codec == nil {
+		c.remove(mediaType)
+		return
+	}
+
+	if _, ok := c.m[mediaType]; !ok {
+		c.mediaTypes = append(c.mediaTypes, mediaType)
+	}
+
+	c.m[mediaType] = codec
+}
+
+// Get will get the codec for a media type
+func (c *Codecs) Get(mediaType string) (codec Codec, ok bool) {
+	codec, ok = c.m[mediaType]
+	return
+}
+
+// MediaTypes will return the registered media types in order of preference
+func (c *Codecs) MediaTypes() []string {
+	return append([]string(nil), c.mediaTypes...)
+}
+
+// Negotiate will return the best codec for a provided Accept header value
+func (c *Codecs) Negotiate(accept string) (mediaType string, codec Codec, ok bool) {
+	if mediaType, ok = negotiate(accept, c.mediaTypes); !ok {
+		return
+	}
+
+	codec = c.m[mediaType]
+	return
+}
+
+// encodable will return the media types whose codecs are able to encode the value
+func (c *Codecs) encodable(mediaTypes []string, value interface{}) (out []string) {
+	out = make([]string, 0, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		if ec, ok := c.m[mt].(encodeChecker); ok && !ec.canEncode(value) {
+			continue
+		}
+
+		out = append(out, mt)
+	}
+
+	return
+}
+
+// ForContentType will return the codec for a provided Content-Type header value
+func (c *Codecs) ForContentType(contentType string) (codec Codec, err error) {
+	if len(contentType) == 0 {
+		// Content type was not provided, fallback to JSON
+		contentType = jsonContentType
+	}
+
+	var mediaType string
+	if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	var ok bool
+	if codec, ok = c.m[mediaType]; !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	return
+}
+
+func (c *Codecs) remove(mediaType string) {
+	delete(c.m, mediaType)
+	for i, mt := range c.mediaTypes {
+		if mt == mediaType {
+			c.mediaTypes = append(c.mediaTypes[:i], c.mediaTypes[i+1:]...)
+			return
+		}
+	}
+}
+
+type jsonCodec struct{}
+
+func (j *jsonCodec) Encode(w io.Writer, value interface{}) error {
+	return jsonAPI.Encode(w, value)
+}
+
+func (j *jsonCodec) Decode(r io.Reader, value interface{}) error {
+	return jsonAPI.Decode(r, value)
+}
+
+type xmlCodec struct{}
+
+func (x *xmlCodec) Encode(w io.Writer, value interface{}) error {
+	return xml.NewEncoder(w).Encode(value)
+}
+
+func (x *xmlCodec) Decode(r io.Reader, value interface{}) error {
+	return xml.NewDecoder(r).Decode(value)
+}
+
+// encodeChecker is implemented by codecs which can only encode some values, they are not negotiated for other values
+type encodeChecker interface {
+	canEncode(value interface{}) bool
+}
+
+type formCodec struct{}
+
+// canEncode will return whether or not the value can be encoded as a form, only structs, maps and form.Marshalers can
+func (f *formCodec) canEncode(value interface{}) bool {
+	if _, ok := value.(form.Marshaler); ok {
+		return true
+	}
+
+	rval := reflect.ValueOf(value)
+	for rval.Kind() == reflect.Ptr || rval.Kind() == reflect.Interface {
+		if rval.IsNil() {
+			return true
+		}
+
+		rval = rval.Elem()
+	}
+
+	switch rval.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Invalid:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f *formCodec) Encode(w io.Writer, value interface{}) error {
+	return form.NewEncoder(w).Encode(value)
+}
+
+func (f *formCodec) Decode(r io.Reader, value interface{}) error {
+	return formFieldErrors("body", form.NewDecoder(r).Decode(value))
+}
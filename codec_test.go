@@ -0,0 +1,190 @@
+package httpserve
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_Write(t *testing.T) {
+	type testXMLStruct struct {
+		Name string `xml:"name" json:"name" form:"name"`
+	}
+
+	tcs := []struct {
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{accept: "", status: 200, contentType: "application/json", body: "{\"name\":\"John Doe\"}\n"},
+		{accept: "application/xml", status: 200, contentType: "application/xml", body: "<testXMLStruct><name>John Doe</name></testXMLStruct>"},
+		{accept: "application/x-www-form-urlencoded", status: 200, contentType: "application/x-www-form-urlencoded", body: "name=John+Doe"},
+		{accept: "application/msgpack", status: 200, contentType: "application/msgpack", body: "\x81\xa4name\xa8John Doe"},
+		{accept: "application/cbor", status: 200, contentType: "application/cbor", body: "\xa1\x64name\x68John Doe"},
+		{accept: "text/csv", status: 406, contentType: "text/plain", body: "406, not acceptable"},
+	}
+
+	for _, tc := range tcs {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		newContext(w, req, nil).Write(200, testXMLStruct{Name: "John Doe"})
+
+		if w.Code != tc.status {
+			t.Fatalf("invalid status code for \"%s\", expected %d and received %d", tc.accept, tc.status, w.Code)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Fatalf("invalid content type for \"%s\", expected \"%s\" and received \"%s\"", tc.accept, tc.contentType, ct)
+		}
+
+		if body := w.Body.String(); body != tc.body {
+			t.Fatalf("invalid body for \"%s\", expected %q and received %q", tc.accept, tc.body, body)
+		}
+	}
+}
+
+func TestContext_Bind(t *testing.T) {
+	type testStruct struct {
+		Name string `json:"name" xml:"name" form:"name"`
+	}
+
+	tcs := []struct {
+		contentType string
+		body        string
+		err         error
+	}{
+		{contentType: "application/json", body: `{"name":"John Doe"}`},
+		{contentType: "application/xml; charset=utf-8", body: `<testStruct><name>John Doe</name></testStruct>`},
+		{contentType: "application/x-www-form-urlencoded", body: `name=John+Doe`},
+		{contentType: "application/msgpack", body: "\x81\xa4name\xa8John Doe"},
+		{contentType: "application/cbor", body: "\xa1\x64name\x68John Doe"},
+		{contentType: "text/csv", body: `name\nJohn Doe`, err: ErrUnsupportedMediaType},
+	}
+
+	for _, tc := range tcs {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+
+		var ts testStruct
+		err := newContext(httptest.NewRecorder(), req, nil).Bind(&ts)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("invalid error for \"%s\", expected %v and received %v", tc.contentType, tc.err, err)
+		}
+
+		if tc.err != nil {
+			if code := ErrorStatusCode(err, 400); code != 415 {
+				t.Fatalf("invalid status code, expected %d and received %d", 415, code)
+			}
+
+			continue
+		}
+
+		if ts.Name != "John Doe" {
+			t.Fatalf("invalid name for \"%s\", expected \"%s\" and received \"%s\"", tc.contentType, "John Doe", ts.Name)
+		}
+	}
+}
+
+func TestJSONValue_codecs(t *testing.T) {
+	type testStruct struct {
+		Name string `json:"name"`
+		Age  int    `json:"age,omitempty"`
+	}
+
+	codecs := NewCodecs()
+	for _, mediaType := range []string{"application/msgpack", "application/cbor"} {
+		codec, ok := codecs.Get(mediaType)
+		if !ok {
+			t.Fatalf("expected codec for \"%s\"", mediaType)
+		}
+
+		var buf bytes.Buffer
+		val := JSONValue{Data: testStruct{Name: "John Doe"}, Meta: Meta{"total": 1}}
+		if err := codec.Encode(&buf, val); err != nil {
+			t.Fatal(err)
+		}
+
+		var generic map[string]interface{}
+		if err := codec.Decode(bytes.NewReader(buf.Bytes()), &generic); err != nil {
+			t.Fatal(err)
+		}
+
+		data, ok := generic["data"].(map[string]interface{})
+		if !ok || data["name"] != "John Doe" {
+			t.Fatalf("invalid data for \"%s\": %#v", mediaType, generic)
+		}
+
+		if _, ok = data["age"]; ok {
+			t.Fatalf("expected empty age to be omitted for \"%s\"", mediaType)
+		}
+
+		var ts testStruct
+		decoded := JSONValue{Data: &ts}
+		if err := codec.Decode(bytes.NewReader(buf.Bytes()), &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		if ts.Name != "John Doe" || decoded.Meta == nil {
+			t.Fatalf("invalid decoded value for \"%s\": %+v", mediaType, decoded)
+		}
+
+		buf.Reset()
+		errVal := JSONValue{Errors: []error{&Error{Message: "invalid name", Field: "name"}}}
+		if err := codec.Encode(&buf, errVal); err != nil {
+			t.Fatal(err)
+		}
+
+		decoded = JSONValue{}
+		if err := codec.Decode(bytes.NewReader(buf.Bytes()), &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(decoded.Errors) != 1 || decoded.Errors[0].Error() != "invalid name" || NewError(decoded.Errors[0]).Field != "name" {
+			t.Fatalf("invalid decoded errors for \"%s\": %+v", mediaType, decoded.Errors)
+		}
+
+		buf.Reset()
+		if err := codec.Encode(&buf, map[string]interface{}{"errors": []interface{}{nil}}); err != nil {
+			t.Fatal(err)
+		}
+
+		decoded = JSONValue{}
+		if err := codec.Decode(bytes.NewReader(buf.Bytes()), &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(decoded.Errors) != 0 {
+			t.Fatalf("expected null errors to be skipped for \"%s\": %+v", mediaType, decoded.Errors)
+		}
+	}
+}
+
+func TestContext_Write_form_unencodable(t *testing.T) {
+	tcs := []struct {
+		accept      string
+		status      int
+		contentType string
+	}{
+		{accept: "application/x-www-form-urlencoded", status: 406, contentType: "text/plain"},
+		{accept: "application/x-www-form-urlencoded, application/json;q=0.5", status: 200, contentType: "application/json"},
+	}
+
+	for _, tc := range tcs {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		newContext(w, req, nil).Write(200, []string{"John Doe"})
+
+		if w.Code != tc.status {
+			t.Fatalf("invalid status code for \"%s\", expected %d and received %d", tc.accept, tc.status, w.Code)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Fatalf("invalid content type for \"%s\", expected \"%s\" and received \"%s\"", tc.accept, tc.contentType, ct)
+		}
+	}
+}
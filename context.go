@@ -8,18 +8,20 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/bytedance/sonic"
 	"github.com/vroomy/httpserve/form"
 )
 
 const formContentType = "application/x-www-form-urlencoded"
 
 // bufPool pools byte buffers used for JSON decoding and encoding,
-// eliminating per-request allocations from json.NewDecoder / sonic.ConfigDefault.NewEncoder.
+// eliminating per-request allocations from per-call encoders.
 var bufPool = sync.Pool{
 	New: func() interface{} { return new(bytes.Buffer) },
 }
 
+// defaultCodecs are used by contexts which are not served by a Router
+var defaultCodecs = NewCodecs()
+
 // ctxPool pools Context objects to avoid per-request heap allocation
 // of the Context struct, its Storage map, and its Params slice.
 var ctxPool = sync.Pool{
@@ -38,6 +40,14 @@ func acquireContext(w http.ResponseWriter, r *http.Request) *Context {
 	c.completed = false
 	c.statusCode = 0
 	c.errorFn = nil
+	c.router = nil
+	c.route = nil
+	c.maxBodySize = 0
+	c.body = nil
+	c.responseSchema = nil
+	c.fields = nil
+	c.envelope = nil
+	c.multipart = c.multipart[:0]
 	// Clear storage without re-allocating the map.
 	for k := range c.s {
 		delete(c.s, k)
@@ -53,6 +63,18 @@ func releaseContext(c *Context) {
 	c.writer = nil
 	c.request = nil
 	c.errorFn = nil
+	c.router = nil
+	c.route = nil
+	if c.body != nil {
+		// Remove any temporary file used to buffer the request body
+		c.body.close()
+		c.body = nil
+	}
+	for _, dec := range c.multipart {
+		// Remove any temporary files used to buffer multipart files
+		dec.RemoveAll()
+	}
+	c.multipart = c.multipart[:0]
 	ctxPool.Put(c)
 }
 
@@ -69,6 +91,8 @@ func newContext(w http.ResponseWriter, r *http.Request, p Params) *Context {
 // Context is the request context
 type Context struct {
 	errorFn func(error)
+	// router is the Router which is serving the context, nil for contexts created outside of a Router
+	router *Router
 
 	// Internal context storage, used by Context.Get and Context.Put.
 	// Lazily initialized on first Put to avoid allocation on requests that
@@ -82,6 +106,21 @@ type Context struct {
 	// Status code of response
 	statusCode int
 
+	// Maximum size of the request body, zero is unlimited
+	maxBodySize int64
+	// Buffered request body, set by Context.Body
+	body *bufferedBody
+	// Schema JSON responses are validated against, set by the ResponseSchema handler
+	responseSchema *Schema
+	// Sparse fieldset configuration, set by the SparseFields handler
+	fields *FieldsConfig
+	// Matched route, nil for unmatched requests and contexts created outside of a Router
+	route *route
+	// JSON response envelope, set by the JSONEnvelope handler
+	envelope Envelope
+	// Multipart decoders used to bind the request, their temporary files are removed on release
+	multipart []*form.MultipartDecoder
+
 	writer  http.ResponseWriter
 	request *http.Request
 
@@ -89,26 +128,79 @@ type Context struct {
 }
 
 // Bind is a helper function which binds the request body to a provided value to be parsed as the inbound content type
+//
+// The decoder is selected from the registered codecs, ErrUnsupportedMediaType is returned when none match.
+// For routes declaring the media types they consume, bodies without a
+// Content-Type are decoded as the first declared media type.
 func (c *Context) Bind(value interface{}) (err error) {
-	defer c.request.Body.Close()
-	contentType := c.request.Header.Get("Content-Type")
-	if strings.HasPrefix(contentType, formContentType) {
-		return form.NewDecoder(c.request.Body).Decode(value)
+	if err = c.bind(value); err != nil {
+		return
 	}
-	// Stream directly from body — no intermediate buffer or pool operations.
-	return sonic.ConfigDefault.NewDecoder(c.request.Body).Decode(value)
+
+	return c.autoValidate(value)
 }
 
 // BindJSON is a helper function which binds the request body to a provided value to be parsed as JSON
 func (c *Context) BindJSON(value interface{}) (err error) {
-	defer c.request.Body.Close()
-	return sonic.ConfigDefault.NewDecoder(c.request.Body).Decode(value)
+	if err = c.bindJSON(value); err != nil {
+		return
+	}
+
+	return c.autoValidate(value)
 }
 
 // BindForm is a helper function which binds the request body to a provided value to be parsed as an HTML form
 func (c *Context) BindForm(value interface{}) (err error) {
+	if err = c.bindForm(value); err != nil {
+		return
+	}
+
+	return c.autoValidate(value)
+}
+
+func (c *Context) bind(value interface{}) (err error) {
+	if c.isMultipart() {
+		return c.bindMultipart(value, nil)
+	}
+
 	defer c.request.Body.Close()
-	return form.NewDecoder(c.request.Body).Decode(value)
+	contentType, ok := c.consumedType()
+	if !ok {
+		return ErrUnsupportedMediaType
+	}
+
+	var codec Codec
+	if codec, err = c.codecs().ForContentType(contentType); err != nil {
+		return
+	}
+
+	var body io.Reader
+	if body, err = c.bodyReader(); err != nil {
+		return
+	}
+
+	// Stream directly from body — no intermediate buffer or pool operations.
+	return codec.Decode(body, value)
+}
+
+func (c *Context) bindJSON(value interface{}) (err error) {
+	defer c.request.Body.Close()
+	var body io.Reader
+	if body, err = c.bodyReader(); err != nil {
+		return
+	}
+
+	return jsonAPI.Decode(body, value)
+}
+
+func (c *Context) bindForm(value interface{}) (err error) {
+	defer c.request.Body.Close()
+	var body io.Reader
+	if body, err = c.bodyReader(); err != nil {
+		return
+	}
+
+	return formFieldErrors("body", form.NewDecoder(body).Decode(value))
 }
 
 // AddHook will add a hook function to be ran after the context has completed
@@ -191,6 +283,18 @@ func (c *Context) WriteReader(statusCode int, contentType string, r io.Reader) {
 
 // WriteJSON will write JSON bytes to the http response body
 func (c *Context) WriteJSON(statusCode int, value interface{}) {
+	c.writeJSON(statusCode, value, nil)
+}
+
+// WriteJSONWithMeta will write JSON bytes to the http response body, including the provided meta
+//
+// Meta is included by the envelope alongside the data, such as {"data": ..., "meta": {"page": 2}}
+// for the DefaultEnvelope.
+func (c *Context) WriteJSONWithMeta(statusCode int, value interface{}, meta Meta) {
+	c.writeJSON(statusCode, value, meta)
+}
+
+func (c *Context) writeJSON(statusCode int, value interface{}, meta Meta) {
 	if c.completed {
 		c.errorFn(ErrContextIsClosed)
 		return
@@ -202,12 +306,23 @@ func (c *Context) WriteJSON(statusCode int, value interface{}) {
 		return
 	}
 
+	// Project the value to the requested sparse fieldset, if enabled
+	statusCode, value = c.projectFields(statusCode, value)
+
+	if statusCode >= 400 && acceptsProblem(c.request) {
+		if p, ok := newProblemFromValue(statusCode, value); ok {
+			// Client accepts problem details, write the error value as a problem
+			c.writeProblem(statusCode, p)
+			return
+		}
+	}
+
 	var (
-		resp JSONValue
+		resp interface{}
 		err  error
 	)
 
-	if resp, err = makeJSONValue(statusCode, value); err != nil {
+	if resp, err = c.jsonEnvelope().Wrap(statusCode, value, meta); err != nil {
 		c.errorFn(err)
 		return
 	}
@@ -216,11 +331,13 @@ func (c *Context) WriteJSON(statusCode int, value interface{}) {
 	buf := bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 	defer bufPool.Put(buf)
-	if err = sonic.ConfigDefault.NewEncoder(buf).Encode(resp); err != nil {
+	if err = jsonAPI.Encode(buf, resp); err != nil {
 		c.errorFn(err)
 		return
 	}
 
+	c.validateResponse(buf.Bytes())
+
 	// Set content type
 	c.setContentType("application/json")
 	// Set status code
@@ -231,6 +348,160 @@ func (c *Context) WriteJSON(statusCode int, value interface{}) {
 	}
 }
 
+// Write will write a value using the registered codec which best matches the request's Accept header
+//
+// When no codec is acceptable, a 406 response is written instead. Routes
+// declaring the media types they produce are limited to those media types.
+func (c *Context) Write(statusCode int, value interface{}) {
+	if c.completed {
+		c.errorFn(ErrContextIsClosed)
+		return
+	}
+	defer c.close()
+
+	if redirected := c.tryRedirect(statusCode); redirected {
+		// Request was redirected, return
+		return
+	}
+
+	if statusCode >= 400 && acceptsProblem(c.request) {
+		if p, ok := newProblemFromValue(statusCode, value); ok {
+			// Client accepts problem details, write the error value as a problem
+			c.writeProblem(statusCode, p)
+			return
+		}
+	}
+
+	switch v := value.(type) {
+	case error:
+		value = NewError(v)
+	case []error:
+		value = newErrors(v)
+	}
+
+	mediaType, codec, ok := c.negotiateCodec(value)
+	if !ok {
+		c.writeNotAcceptable()
+		return
+	}
+
+	// Encode into pooled buffer first so we don't commit headers if encoding fails.
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufPool.Put(buf)
+	if err := codec.Encode(buf, value); err != nil {
+		c.errorFn(err)
+		return
+	}
+
+	// Set content type
+	c.setContentType(mediaType)
+	// Set status code
+	c.setStatusCode(statusCode)
+
+	if _, err := buf.WriteTo(c.writer); err != nil {
+		c.errorFn(err)
+	}
+}
+
+// WriteProblem will write an RFC 9457 problem details response, the status code is derived from the Problem
+func (c *Context) WriteProblem(p *Problem) {
+	if c.completed {
+		c.errorFn(ErrContextIsClosed)
+		return
+	}
+	defer c.close()
+
+	statusCode := p.Status
+	if statusCode == 0 {
+		statusCode = http.StatusInternalServerError
+	}
+
+	c.writeProblem(statusCode, p)
+}
+
+// Respond will write a Responder to the http response body
+func (c *Context) Respond(resp Responder) {
+	if c.completed {
+		c.errorFn(ErrContextIsClosed)
+		return
+	}
+	defer c.close()
+
+	switch r := resp.(type) {
+	case *AdoptResponse:
+		// Response writer has been adopted by the handler, nothing to write
+		return
+	case *RedirectResponse:
+		c.redirect(r.code, r.url)
+		return
+	case *JSONResponse:
+		// Responders may be shared between requests, the envelope and fields are set on a copy
+		cp := *r
+		if cp.envelope == nil {
+			cp.envelope = c.jsonEnvelope()
+		}
+
+		resp = &cp
+		if cp.code >= 400 || cp.fields != nil {
+			break
+		}
+
+		if paths, err := c.requestedFields(cp.val); err != nil {
+			resp = NewJSONResponse(400, err)
+		} else if len(paths) > 0 {
+			cp.SetFields(paths...)
+		}
+	case *JSONPResponse:
+		if !ValidJSONPCallback(r.callback) {
+			c.writeJSON(400, ErrInvalidCallback, nil)
+			return
+		}
+
+		if r.envelope == nil {
+			// Responders may be shared between requests, the envelope is set on a copy
+			cp := *r
+			cp.envelope = c.jsonEnvelope()
+			resp = &cp
+		}
+
+		// Prevent browsers from interpreting the response as anything other than a script
+		c.writer.Header().Set("X-Content-Type-Options", "nosniff")
+	}
+
+	statusCode := resp.StatusCode()
+	if redirected := c.tryRedirect(statusCode); redirected {
+		// Request was redirected, return
+		return
+	}
+
+	if statusCode == 204 {
+		c.setStatusCode(statusCode)
+		return
+	}
+
+	// Write into pooled buffer first so we don't commit headers if the responder fails.
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufPool.Put(buf)
+	if _, err := resp.WriteTo(buf); err != nil {
+		c.errorFn(err)
+		return
+	}
+
+	if contentType := resp.ContentType(); len(contentType) > 0 {
+		// Set content type
+		c.setContentType(contentType)
+	}
+
+	// Set status code
+	c.setStatusCode(statusCode)
+
+	if _, err := buf.WriteTo(c.writer); err != nil {
+		c.errorFn(err)
+	}
+}
+
 // WriteNoContent will write a no content response
 func (c *Context) WriteNoContent() {
 	if c.completed {
@@ -282,6 +553,61 @@ func (c *Context) setContentType(contentType string) {
 	header.Set("Content-Type", contentType)
 }
 
+func (c *Context) writeProblem(statusCode int, p *Problem) {
+	if p.Status == 0 {
+		// Copy the problem to avoid mutating the caller's value
+		cp := *p
+		cp.Status = statusCode
+		p = &cp
+	}
+
+	bs, err := p.MarshalJSON()
+	if err != nil {
+		c.errorFn(err)
+		return
+	}
+
+	// Set content type
+	c.setContentType(problemContentType)
+	// Set status code
+	c.setStatusCode(statusCode)
+
+	if _, err = c.writer.Write(bs); err != nil {
+		c.errorFn(err)
+	}
+}
+
+func (c *Context) writeNotAcceptable() {
+	if c.prefersProblem() {
+		c.writeProblem(http.StatusNotAcceptable, NewProblem(http.StatusNotAcceptable, nil))
+		return
+	}
+
+	c.setContentType("text/plain")
+	c.setStatusCode(http.StatusNotAcceptable)
+	if _, err := c.writer.Write([]byte("406, not acceptable")); err != nil {
+		c.errorFn(err)
+	}
+}
+
+// codecs will return the codecs of the serving Router, or the default codecs when not served by a Router
+func (c *Context) codecs() *Codecs {
+	if c.router != nil {
+		return c.router.codecs
+	}
+
+	return defaultCodecs
+}
+
+// prefersProblem will return whether or not the built-in error responses should be written as problem details
+func (c *Context) prefersProblem() bool {
+	if c.router != nil && c.router.problemDetails {
+		return true
+	}
+
+	return acceptsProblem(c.request)
+}
+
 func (c *Context) processHandlers(hs []Handler) {
 	// Iterate through the provided handlers
 	for _, h := range hs {
@@ -0,0 +1,73 @@
+package httpserve
+
+// DefaultEnvelope wraps values as {"data": ..., "errors": [...], "meta": {...}}
+var DefaultEnvelope Envelope = EnvelopeFunc(defaultEnvelope)
+
+// BareEnvelope writes successful values as-is, without a wrapping object
+//
+// Error responses are still written as {"errors": [...], "meta": {...}} so
+// clients can rely on a single error shape. Meta is omitted from successful responses.
+var BareEnvelope Envelope = EnvelopeFunc(bareEnvelope)
+
+// Meta is additional information included alongside the response data, such as pagination or request IDs
+type Meta map[string]interface{}
+
+// Envelope determines the shape of JSON response bodies
+type Envelope interface {
+	// Wrap will return the value to be encoded for the provided status code, response value and meta
+	Wrap(statusCode int, value interface{}, meta Meta) (wrapped interface{}, err error)
+}
+
+// EnvelopeFunc is a func which implements Envelope
+type EnvelopeFunc func(statusCode int, value interface{}, meta Meta) (wrapped interface{}, err error)
+
+// Wrap will call the envelope func
+func (fn EnvelopeFunc) Wrap(statusCode int, value interface{}, meta Meta) (wrapped interface{}, err error) {
+	return fn(statusCode, value, meta)
+}
+
+// JSONEnvelope will return a Handler which sets the envelope for JSON responses
+//
+// Use this as a Group handler to set the envelope for every route within the group,
+// the server wide envelope is set using Serve.SetEnvelope.
+func JSONEnvelope(e Envelope) Handler {
+	return func(ctx *Context) {
+		ctx.envelope = e
+	}
+}
+
+func defaultEnvelope(statusCode int, value interface{}, meta Meta) (wrapped interface{}, err error) {
+	var val JSONValue
+	if val, err = makeJSONValue(statusCode, value); err != nil {
+		return
+	}
+
+	val.Meta = meta
+	return val, nil
+}
+
+func bareEnvelope(statusCode int, value interface{}, meta Meta) (wrapped interface{}, err error) {
+	if statusCode < 400 {
+		return value, nil
+	}
+
+	var val JSONValue
+	if val, err = makeJSONValue(statusCode, value); err != nil {
+		return
+	}
+
+	val.Meta = meta
+	return val, nil
+}
+
+// jsonEnvelope will return the envelope for the context, falling back to the router and default envelopes
+func (c *Context) jsonEnvelope() Envelope {
+	switch {
+	case c.envelope != nil:
+		return c.envelope
+	case c.router != nil && c.router.envelope != nil:
+		return c.router.envelope
+	default:
+		return DefaultEnvelope
+	}
+}
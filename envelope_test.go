@@ -0,0 +1,147 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_WriteJSONWithMeta(t *testing.T) {
+	tcs := []struct {
+		name     string
+		envelope Envelope
+		code     int
+		value    interface{}
+		meta     Meta
+		expected string
+	}{
+		{
+			name:     "default",
+			code:     200,
+			value:    []int{1, 2},
+			meta:     Meta{"page": 2, "total": 10},
+			expected: `{"data":[1,2],"meta":{"page":2,"total":10}}`,
+		},
+		{
+			name:     "default without meta",
+			code:     200,
+			value:    []int{1, 2},
+			expected: `{"data":[1,2]}`,
+		},
+		{
+			name:     "default error",
+			code:     400,
+			value:    errors.New("foo"),
+			meta:     Meta{"requestID": "abc"},
+			expected: `{"errors":[{"message":"foo"}],"meta":{"requestID":"abc"}}`,
+		},
+		{
+			name:     "bare",
+			envelope: BareEnvelope,
+			code:     200,
+			value:    []int{1, 2},
+			meta:     Meta{"page": 2},
+			expected: `[1,2]`,
+		},
+		{
+			name:     "bare error",
+			envelope: BareEnvelope,
+			code:     400,
+			value:    errors.New("foo"),
+			expected: `{"errors":[{"message":"foo"}]}`,
+		},
+		{
+			name: "custom",
+			envelope: EnvelopeFunc(func(statusCode int, value interface{}, meta Meta) (interface{}, error) {
+				return map[string]interface{}{"result": value, "status": statusCode}, nil
+			}),
+			code:     200,
+			value:    "bar",
+			expected: `{"result":"bar","status":200}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx := newContext(w, httptest.NewRequest("GET", "/", nil), nil)
+			if tc.envelope != nil {
+				JSONEnvelope(tc.envelope)(ctx)
+			}
+
+			ctx.WriteJSONWithMeta(tc.code, tc.value, tc.meta)
+			if w.Code != tc.code {
+				t.Fatalf("invalid status code, expected %d and received %d", tc.code, w.Code)
+			}
+
+			assertJSONEqual(t, tc.expected, w.Body.String())
+		})
+	}
+}
+
+func TestRouter_SetEnvelope(t *testing.T) {
+	r := newRouter()
+	r.SetEnvelope(BareEnvelope)
+	if err := r.GET("/", newHandler([]Handler{func(ctx *Context) {
+		ctx.WriteJSON(200, map[string]string{"foo": "bar"})
+	}})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.GET("/wrapped", newHandler([]Handler{JSONEnvelope(DefaultEnvelope), func(ctx *Context) {
+		resp := NewJSONResponse(200, map[string]string{"foo": "bar"})
+		resp.SetMeta(Meta{"next": "abc"})
+		ctx.Respond(resp)
+	}})); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assertJSONEqual(t, `{"foo":"bar"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/wrapped", nil))
+	assertJSONEqual(t, `{"data":{"foo":"bar"},"meta":{"next":"abc"}}`, w.Body.String())
+}
+
+func TestContext_Respond_shared_envelope(t *testing.T) {
+	r := newRouter()
+	jsonResp := NewJSONResponse(200, "foo")
+	jsonPResp := NewJSONPResponse("cb", "foo")
+	if err := r.GET("/bare/json", newHandler([]Handler{JSONEnvelope(BareEnvelope), func(ctx *Context) { ctx.Respond(jsonResp) }})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.GET("/bare/jsonp", newHandler([]Handler{JSONEnvelope(BareEnvelope), func(ctx *Context) { ctx.Respond(jsonPResp) }})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.GET("/json", newHandler([]Handler{func(ctx *Context) { ctx.Respond(jsonResp) }})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.GET("/jsonp", newHandler([]Handler{func(ctx *Context) { ctx.Respond(jsonPResp) }})); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		url      string
+		expected string
+	}{
+		{url: "/bare/json", expected: "\"foo\"\n"},
+		{url: "/json", expected: "{\"data\":\"foo\"}\n"},
+		{url: "/bare/jsonp", expected: "/**/cb(\"foo\");\n"},
+		{url: "/jsonp", expected: "/**/cb({\"data\":\"foo\",\"meta\":{\"status\":200}});\n"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tc.url, nil))
+		if body := w.Body.String(); body != tc.expected {
+			t.Fatalf("invalid body for %s, expected %q and received %q", tc.url, tc.expected, body)
+		}
+	}
+
+	if jsonResp.envelope != nil || jsonPResp.envelope != nil {
+		t.Fatal("expected shared responders not to be modified")
+	}
+}
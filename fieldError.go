@@ -0,0 +1,113 @@
+package httpserve
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vroomy/httpserve/form"
+)
+
+// FieldError is an error associated with a field of a request
+type FieldError struct {
+	// Source is the part of the request the field was read from (e.g. "query" or "header")
+	Source string
+	// Field is the name of the field as referenced by the request
+	Field string
+	// Value is the raw value of the field
+	Value string
+	// Code is a machine-readable error code
+	Code string
+	// Err is the underlying error
+	Err error
+}
+
+// Error will return the error message
+func (f *FieldError) Error() string {
+	return f.Field + ": " + f.Err.Error()
+}
+
+// Unwrap will return the underlying error
+func (f *FieldError) Unwrap() error {
+	return f.Err
+}
+
+// ErrorCode will return the error code
+func (f *FieldError) ErrorCode() string {
+	return f.Code
+}
+
+// ErrorField will return the field name
+func (f *FieldError) ErrorField() string {
+	return f.Field
+}
+
+// ErrorDetails will return the source and raw value of the field
+func (f *FieldError) ErrorDetails() interface{} {
+	if len(f.Source) == 0 {
+		return nil
+	}
+
+	return map[string]string{
+		"source": f.Source,
+		"value":  f.Value,
+	}
+}
+
+// FieldErrors is a list of field errors
+type FieldErrors []*FieldError
+
+// Error will return the error messages joined by a semicolon
+func (f FieldErrors) Error() string {
+	msgs := make([]string, 0, len(f))
+	for _, err := range f {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap will return the field errors as an error slice
+func (f FieldErrors) Unwrap() []error {
+	return f.errors()
+}
+
+func (f FieldErrors) errors() (errs []error) {
+	errs = make([]error, 0, len(f))
+	for _, err := range f {
+		errs = append(errs, err)
+	}
+
+	return
+}
+
+// err will return the field errors as an error, nil is returned when the list is empty
+func (f FieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	return f
+}
+
+// formFieldErrors will convert form decode errors to field errors of the provided source, other errors are returned as-is
+func formFieldErrors(source string, err error) error {
+	var derrs form.DecodeErrors
+	if !errors.As(err, &derrs) {
+		return err
+	}
+
+	errs := make(FieldErrors, 0, len(derrs))
+	for _, d := range derrs {
+		code := "invalid"
+		switch {
+		case errors.Is(d.Err, form.ErrUnknownKey):
+			code = "unknown_field"
+		case errors.Is(d.Err, form.ErrFileTypeNotAllowed):
+			code = "unsupported_type"
+		}
+
+		errs = append(errs, &FieldError{Source: source, Field: d.Key, Value: d.Value, Code: code, Err: d.Err})
+	}
+
+	return errs
+}
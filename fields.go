@@ -0,0 +1,241 @@
+package httpserve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+const defaultFieldsParam = "fields"
+
+// errUnknownField is the error of a requested field which does not exist
+var errUnknownField = errors.New("unknown field")
+
+// FieldsConfig is the configuration for sparse fieldsets
+type FieldsConfig struct {
+	// Param is the query parameter containing the comma separated field paths, defaults to "fields"
+	Param string
+	// Strict will respond with a 400 when a requested field does not exist
+	Strict bool
+}
+
+func (f *FieldsConfig) param() string {
+	if len(f.Param) == 0 {
+		return defaultFieldsParam
+	}
+
+	return f.Param
+}
+
+// SparseFields will return a Handler which enables sparse fieldsets for JSON responses
+//
+// When enabled, successful responses written with WriteJSON or a JSONResponse
+// are projected to the dot-separated field paths requested within the query
+// (e.g. ?fields=id,name,owner.email). Struct fields are matched by their json
+// tag, and projection is applied to each element of a slice.
+func SparseFields(cfg FieldsConfig) Handler {
+	return func(ctx *Context) {
+		ctx.fields = &cfg
+	}
+}
+
+// SetFields will set the field paths the response data is projected to
+func (j *JSONResponse) SetFields(paths ...string) {
+	j.fields = paths
+}
+
+// requestedFields will return the field paths requested for a value, unknown fields return an error in strict mode
+func (c *Context) requestedFields(value interface{}) (paths []string, err error) {
+	if c.fields == nil {
+		return
+	}
+
+	param := c.fields.param()
+	raw := c.request.URL.Query().Get(param)
+	if len(raw) == 0 {
+		return
+	}
+
+	var errs FieldErrors
+	for _, path := range strings.Split(raw, ",") {
+		if path = strings.TrimSpace(path); len(path) == 0 {
+			continue
+		}
+
+		if c.fields.Strict && !hasFieldPath(reflect.TypeOf(value), strings.Split(path, ".")) {
+			errs = append(errs, &FieldError{Source: "query", Field: param, Value: path, Code: "unknown_field", Err: errUnknownField})
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	if err = errs.err(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// projectFields will project a successful response value to the requested fields
+//
+// When the requested fields are invalid, a 400 status code and the error are returned in place of the value.
+func (c *Context) projectFields(statusCode int, value interface{}) (int, interface{}) {
+	if statusCode >= 400 || c.fields == nil {
+		return statusCode, value
+	}
+
+	paths, err := c.requestedFields(value)
+	if err != nil {
+		return 400, err
+	}
+
+	if len(paths) == 0 {
+		return statusCode, value
+	}
+
+	if value, err = projectValue(value, paths); err != nil {
+		return 500, err
+	}
+
+	return statusCode, value
+}
+
+// projectValue will return the JSON representation of a value, limited to the provided field paths
+func projectValue(value interface{}, paths []string) (projected interface{}, err error) {
+	var bs []byte
+	if bs, err = jsonAPI.Marshal(value); err != nil {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	// Numbers are decoded as json.Number to avoid precision loss
+	dec.UseNumber()
+	if err = dec.Decode(&projected); err != nil {
+		return
+	}
+
+	tree := make(fieldTree)
+	for _, path := range paths {
+		tree.add(strings.Split(path, "."))
+	}
+
+	return tree.project(projected), nil
+}
+
+// fieldTree is a tree of requested field paths, a nil subtree includes the entire field
+type fieldTree map[string]fieldTree
+
+func (f fieldTree) add(path []string) {
+	key := path[0]
+	sub, ok := f[key]
+	switch {
+	case len(path) == 1:
+		// Entire field is requested
+		f[key] = nil
+	case ok && sub == nil:
+		// Entire field was already requested
+	default:
+		if sub == nil {
+			sub = make(fieldTree)
+			f[key] = sub
+		}
+
+		sub.add(path[1:])
+	}
+}
+
+func (f fieldTree) project(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(f))
+		for key, sub := range f {
+			val, ok := v[key]
+			if !ok {
+				continue
+			}
+
+			if sub == nil {
+				out[key] = val
+				continue
+			}
+
+			out[key] = sub.project(val)
+		}
+
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = f.project(val)
+		}
+
+		return out
+	default:
+		return value
+	}
+}
+
+// hasFieldPath will return whether or not a field path exists for a type, maps and interfaces accept any key
+func hasFieldPath(rtype reflect.Type, path []string) bool {
+	if rtype == nil || len(path) == 0 {
+		return true
+	}
+
+	switch rtype.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasFieldPath(rtype.Elem(), path)
+	case reflect.Map, reflect.Interface:
+		return true
+	case reflect.Struct:
+		field, ok := jsonField(rtype, path[0])
+		if !ok {
+			return false
+		}
+
+		return hasFieldPath(field.Type, path[1:])
+	default:
+		return false
+	}
+}
+
+// jsonField will return the struct field for a JSON key, including fields of embedded structs
+func jsonField(rtype reflect.Type, key string) (field reflect.StructField, ok bool) {
+	for i := 0; i < rtype.NumField(); i++ {
+		sf := rtype.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if embedded := sf.Type; sf.Anonymous && len(name) == 0 {
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				if field, ok = jsonField(embedded, key); ok {
+					return
+				}
+
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = sf.Name
+		}
+
+		if name == key {
+			return sf, true
+		}
+	}
+
+	return
+}
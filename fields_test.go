@@ -0,0 +1,158 @@
+package httpserve
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type testFieldsOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type testFieldsItem struct {
+	ID    int              `json:"id"`
+	Name  string           `json:"name"`
+	Owner *testFieldsOwner `json:"owner"`
+	Tags  []string         `json:"tags"`
+}
+
+func TestContext_WriteJSON_fields(t *testing.T) {
+	item := testFieldsItem{ID: 1, Name: "foo", Owner: &testFieldsOwner{Name: "bar", Email: "bar@example.com"}, Tags: []string{"a"}}
+	tcs := []struct {
+		name     string
+		cfg      FieldsConfig
+		query    string
+		value    interface{}
+		code     int
+		expected string
+	}{
+		{
+			name:     "no fields",
+			query:    "",
+			value:    item,
+			code:     200,
+			expected: `{"data":{"id":1,"name":"foo","owner":{"name":"bar","email":"bar@example.com"},"tags":["a"]}}`,
+		},
+		{
+			name:     "nested",
+			query:    "?fields=id,owner.email",
+			value:    item,
+			code:     200,
+			expected: `{"data":{"id":1,"owner":{"email":"bar@example.com"}}}`,
+		},
+		{
+			name:     "slice",
+			query:    "?fields=name",
+			value:    []testFieldsItem{item, item},
+			code:     200,
+			expected: `{"data":[{"name":"foo"},{"name":"foo"}]}`,
+		},
+		{
+			name:     "map",
+			query:    "?fields=a",
+			value:    map[string]int{"a": 1, "b": 2},
+			code:     200,
+			expected: `{"data":{"a":1}}`,
+		},
+		{
+			name:     "custom param",
+			cfg:      FieldsConfig{Param: "select"},
+			query:    "?select=id&fields=name",
+			value:    item,
+			code:     200,
+			expected: `{"data":{"id":1}}`,
+		},
+		{
+			name:     "unknown field",
+			query:    "?fields=id,foo",
+			value:    item,
+			code:     200,
+			expected: `{"data":{"id":1}}`,
+		},
+		{
+			name:     "strict unknown field",
+			cfg:      FieldsConfig{Strict: true},
+			query:    "?fields=id,owner.foo",
+			value:    item,
+			code:     400,
+			expected: `{"errors":[{"message":"fields: unknown field","code":"unknown_field","field":"fields","details":{"source":"query","value":"owner.foo"}}]}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/"+tc.query, nil)
+			ctx := newContext(w, req, nil)
+			SparseFields(tc.cfg)(ctx)
+			ctx.WriteJSON(200, tc.value)
+
+			if w.Code != tc.code {
+				t.Fatalf("invalid status code, expected %d and received %d (%s)", tc.code, w.Code, w.Body.String())
+			}
+
+			assertJSONEqual(t, tc.expected, w.Body.String())
+		})
+	}
+}
+
+func TestJSONResponse_SetFields(t *testing.T) {
+	item := testFieldsItem{ID: 1, Name: "foo", Owner: &testFieldsOwner{Name: "bar"}}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/?fields=owner", nil)
+	ctx := newContext(w, req, nil)
+	SparseFields(FieldsConfig{})(ctx)
+	ctx.Respond(NewJSONResponse(200, item))
+	assertJSONEqual(t, `{"data":{"owner":{"name":"bar","email":""}}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	resp := NewJSONResponse(200, item)
+	resp.SetFields("id", "name")
+	if _, err := resp.WriteTo(w); err != nil {
+		t.Fatal(err)
+	}
+
+	assertJSONEqual(t, `{"data":{"id":1,"name":"foo"}}`, w.Body.String())
+}
+
+func TestContext_Respond_shared_fields(t *testing.T) {
+	resp := NewJSONResponse(200, testFieldsItem{ID: 1, Name: "foo"})
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{query: "?fields=id", expected: `{"data":{"id":1}}`},
+		{query: "?fields=name", expected: `{"data":{"name":"foo"}}`},
+		{query: "", expected: `{"data":{"id":1,"name":"foo","owner":null,"tags":null}}`},
+	} {
+		w := httptest.NewRecorder()
+		ctx := newContext(w, httptest.NewRequest("GET", "/"+tc.query, nil), nil)
+		SparseFields(FieldsConfig{})(ctx)
+		ctx.Respond(resp)
+		assertJSONEqual(t, tc.expected, w.Body.String())
+	}
+
+	if resp.fields != nil {
+		t.Fatalf("expected shared responder not to be modified, received fields %v", resp.fields)
+	}
+}
+
+func assertJSONEqual(t *testing.T, expected, received string) {
+	t.Helper()
+	var a, b interface{}
+	if err := json.Unmarshal([]byte(expected), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal([]byte(received), &b); err != nil {
+		t.Fatalf("invalid JSON %q: %v", received, err)
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("invalid body, expected %s and received %s", expected, received)
+	}
+}
@@ -2,113 +2,276 @@ package form
 
 import (
 	"bufio"
+	"bytes"
+	"fmt"
 	"io"
 	"net/url"
+	"strings"
+	"sync"
 )
 
-// NewDecoder will initialize a new decoder
-func NewDecoder(r io.Reader) *Decoder {
-	var (
-		d  Decoder
-		ok bool
-	)
+// readerPool pools the buffered readers used to decode streamed input
+var readerPool = sync.Pool{
+	New: func() interface{} { return bufio.NewReader(nil) },
+}
 
-	if d.r, ok = r.(io.RuneReader); !ok {
-		d.r = bufio.NewReader(r)
-	}
+const (
+	// DefaultMaxDepth is the default maximum nesting depth of keys, such as 2 for "items[0][sku]"
+	DefaultMaxDepth = 8
+	// DefaultMaxIndex is the default maximum slice index of keys, such as 0 for "items[0][sku]"
+	DefaultMaxIndex = 1000
+	// DefaultMaxPairs is the default maximum number of key/value pairs
+	DefaultMaxPairs = 1000
+	// DefaultMaxKeyLength is the default maximum length of an encoded key
+	DefaultMaxKeyLength = 512
+	// DefaultMaxValueLength is the default maximum length of an encoded value
+	DefaultMaxValueLength = 1 << 20
+)
 
+// NewDecoder will initialize a new decoder
+func NewDecoder(r io.Reader) *Decoder {
+	var d Decoder
+	d.r = r
+	d.opts.maxDepth = DefaultMaxDepth
+	d.opts.maxIndex = DefaultMaxIndex
+	d.opts.maxPairs = DefaultMaxPairs
+	d.opts.maxKeyLength = DefaultMaxKeyLength
+	d.opts.maxValueLength = DefaultMaxValueLength
 	return &d
 }
 
 // Decoder will decode a value
 type Decoder struct {
-	u Unmarshaler
-	r io.RuneReader
+	u    Unmarshaler
+	r    io.Reader
+	br   *bufio.Reader
+	opts options
 
-	char       rune
-	seenEquals bool
+	// buf holds pairs which exceed the size of the read buffer
+	buf []byte
+
+	pairs int
+	errs  DecodeErrors
+}
+
+// SetMaxDepth will set the maximum nesting depth of keys, keys exceeding it return ErrMaxDepthExceeded
+func (d *Decoder) SetMaxDepth(n int) {
+	d.opts.maxDepth = n
+}
+
+// SetMaxIndex will set the maximum slice index of keys, indexes exceeding it return ErrMaxIndexExceeded
+//
+// The limit also applies to values appended by repeated keys.
+func (d *Decoder) SetMaxIndex(n int) {
+	d.opts.maxIndex = n
+}
+
+// SetMaxPairs will set the maximum number of key/value pairs, forms exceeding it return ErrTooManyPairs
+func (d *Decoder) SetMaxPairs(n int) {
+	d.opts.maxPairs = n
+}
+
+// SetMaxKeyLength will set the maximum length of an encoded key, keys exceeding it return ErrKeyTooLong
+func (d *Decoder) SetMaxKeyLength(n int) {
+	d.opts.maxKeyLength = n
+}
 
-	keyBuf []rune
-	valBuf []rune
+// SetMaxValueLength will set the maximum length of an encoded value, values exceeding it return ErrValueTooLong
+func (d *Decoder) SetMaxValueLength(n int) {
+	d.opts.maxValueLength = n
+}
+
+// SetStrict will set whether or not keys without a matching field result in a DecodeError caused by ErrUnknownKey
+func (d *Decoder) SetStrict(strict bool) {
+	d.opts.strict = strict
 }
 
 // Decode will decode a provided value
+//
+// Repeated keys are appended to slice fields. Nested structs, maps and
+// slices are decoded using bracket (address[city], items[0][sku]) or dot
+// (address.city, items.0.sku) notation.
+//
+// Values which fail to decode are returned as DecodeErrors, containing an
+// error for each failed key. Exceeding a limit (see SetMaxPairs,
+// SetMaxKeyLength, SetMaxValueLength, SetMaxDepth and SetMaxIndex) stops
+// decoding and returns the limit error.
 func (d *Decoder) Decode(value interface{}) (err error) {
-	// Set value for decoder
-	d.setValue(value)
-
-	// Iterate through runes
-	for d.char, _, err = d.r.ReadRune(); err == nil; d.char, _, err = d.r.ReadRune() {
-		switch d.char {
-		case '=':
-			d.processEquals()
-		case '&':
-			err = d.processAmpersand()
-		default:
-			err = d.processChar()
+	d.init(value)
+	switch r := d.r.(type) {
+	case inMemoryReader:
+		// Input is already held in memory, copy it into a single string so keys and values can reference it
+		var sb strings.Builder
+		sb.Grow(r.Len())
+		if _, err = r.WriteTo(&sb); err != nil {
+			return
 		}
 
-		if err != nil {
-			break
-		}
+		err = d.decodeString(sb.String())
+	default:
+		err = d.decodeReader()
 	}
 
-	switch err {
-	case nil:
-	case io.EOF:
+	if err != nil {
+		return
+	}
 
-	default:
+	return d.errs.err()
+}
+
+// unmarshal will decode a query string without copying it
+func (d *Decoder) unmarshal(query string, value interface{}) (err error) {
+	d.init(value)
+	if err = d.decodeString(query); err != nil {
 		return
 	}
 
-	return d.processAmpersand()
+	return d.errs.err()
 }
 
-func (d *Decoder) setValue(value interface{}) {
+func (d *Decoder) init(value interface{}) {
 	var ok bool
-	if d.u, ok = value.(Unmarshaler); ok {
-		return
+	if d.u, ok = value.(Unmarshaler); !ok {
+		d.u = newMapUnmarshaler(value, &d.opts)
 	}
 
-	d.u = newMapUnmarshaler(value)
+	d.pairs = 0
+	d.errs = nil
 }
 
-func (d *Decoder) reset() {
-	d.seenEquals = false
-	d.keyBuf = d.keyBuf[:0]
-	d.valBuf = d.valBuf[:0]
+// decodeString will decode the pairs of a string, keys and values which do not require unescaping are not copied
+func (d *Decoder) decodeString(query string) (err error) {
+	for len(query) > 0 {
+		var pair string
+		pair, query, _ = strings.Cut(query, "&")
+		if err = d.processPair(pair); err != nil {
+			return
+		}
+	}
+
+	return
 }
 
-func (d *Decoder) processEquals() {
-	d.seenEquals = true
+// decodeReader will decode the pairs read from the reader, each pair is copied once
+func (d *Decoder) decodeReader() (err error) {
+	d.br = readerPool.Get().(*bufio.Reader)
+	d.br.Reset(d.r)
+	defer func() {
+		d.br.Reset(nil)
+		readerPool.Put(d.br)
+		d.br = nil
+	}()
+
+	for {
+		var pair []byte
+		pair, err = d.readPair()
+		switch err {
+		case nil:
+			// Remove the trailing ampersand
+			pair = pair[:len(pair)-1]
+		case io.EOF:
+		default:
+			return
+		}
+
+		if perr := d.processPair(string(pair)); perr != nil {
+			return perr
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
 }
 
-func (d *Decoder) processAmpersand() (err error) {
-	if len(d.keyBuf) == 0 && len(d.valBuf) == 0 {
+// readPair will read up to and including the next ampersand
+func (d *Decoder) readPair() (pair []byte, err error) {
+	if pair, err = d.br.ReadSlice('&'); err != bufio.ErrBufferFull {
 		return
 	}
 
-	var key string
-	if key, err = url.QueryUnescape(string(d.keyBuf)); err != nil {
+	// Pair exceeds the read buffer, accumulate it while enforcing the length limits
+	d.buf = append(d.buf[:0], pair...)
+	for err == bufio.ErrBufferFull {
+		if err = d.checkLengths(d.buf); err != nil {
+			return
+		}
+
+		pair, err = d.br.ReadSlice('&')
+		d.buf = append(d.buf, pair...)
+	}
+
+	return d.buf, err
+}
+
+// checkLengths will return an error when a partially read pair exceeds the key or value length limits
+func (d *Decoder) checkLengths(pair []byte) (err error) {
+	key, val, ok := bytes.Cut(pair, []byte{'='})
+	switch {
+	case len(key) > d.opts.maxKeyLength:
+		return fmt.Errorf("%w: maximum is %d", ErrKeyTooLong, d.opts.maxKeyLength)
+	case ok && len(val) > d.opts.maxValueLength:
+		return fmt.Errorf("%w: maximum is %d", ErrValueTooLong, d.opts.maxValueLength)
+	default:
 		return
 	}
+}
 
-	var val string
-	if val, err = url.QueryUnescape(string(d.valBuf)); err != nil {
+func (d *Decoder) processPair(pair string) (err error) {
+	rawKey, rawVal, _ := strings.Cut(pair, "=")
+	if len(rawKey) == 0 && len(rawVal) == 0 {
 		return
 	}
 
-	d.reset()
+	if d.pairs++; d.pairs > d.opts.maxPairs {
+		return fmt.Errorf("%w: maximum is %d", ErrTooManyPairs, d.opts.maxPairs)
+	}
+
+	if len(rawKey) > d.opts.maxKeyLength {
+		return fmt.Errorf("%w: maximum is %d", ErrKeyTooLong, d.opts.maxKeyLength)
+	}
 
-	return d.u.UnmarshalForm(key, val)
-}
+	if len(rawVal) > d.opts.maxValueLength {
+		return fmt.Errorf("%w: maximum is %d", ErrValueTooLong, d.opts.maxValueLength)
+	}
+
+	// Note: QueryUnescape does not allocate when there is nothing to unescape
+	var key, val string
+	if key, err = url.QueryUnescape(rawKey); err != nil {
+		d.errs = append(d.errs, &DecodeError{Key: rawKey, Value: rawVal, Err: err})
+		return nil
+	}
+
+	if val, err = url.QueryUnescape(rawVal); err != nil {
+		d.errs = append(d.errs, &DecodeError{Key: key, Value: rawVal, Err: err})
+		return nil
+	}
 
-func (d *Decoder) processChar() (err error) {
-	if !d.seenEquals {
-		d.keyBuf = append(d.keyBuf, d.char)
+	if err = d.u.UnmarshalForm(key, val); err == nil {
 		return
 	}
 
-	d.valBuf = append(d.valBuf, d.char)
-	return
+	if isLimitError(err) {
+		return
+	}
+
+	// Aggregate value errors so every invalid key is reported
+	d.errs = append(d.errs, &DecodeError{Key: key, Value: val, Err: err})
+	return nil
+}
+
+// inMemoryReader is implemented by readers of data held in memory, such as strings.Reader and bytes.Reader
+type inMemoryReader interface {
+	io.WriterTo
+	Len() int
+}
+
+// options are the decoding options shared with the map unmarshaler
+type options struct {
+	maxDepth       int
+	maxIndex       int
+	maxPairs       int
+	maxKeyLength   int
+	maxValueLength int
+	strict         bool
 }
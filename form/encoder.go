@@ -0,0 +1,316 @@
+package form
+
+import (
+	"bufio"
+	"encoding"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshaler is an encoding helper interface, the counterpart of Unmarshaler
+//
+// Each key/value pair is provided to add, keys are relative to the value
+// (nested values are prefixed by the encoder using bracket notation).
+type Marshaler interface {
+	MarshalForm(add func(key, value string)) error
+}
+
+// Marshal will encode the provided value as a form
+func Marshal(value interface{}) (query string, err error) {
+	var sb strings.Builder
+	if err = NewEncoder(&sb).Encode(value); err != nil {
+		return
+	}
+
+	return sb.String(), nil
+}
+
+// NewEncoder will initialize a new encoder
+func NewEncoder(w io.Writer) *Encoder {
+	var e Encoder
+	e.w = w
+	return &e
+}
+
+// Encoder will encode a value
+type Encoder struct {
+	w io.Writer
+
+	bw    *bufio.Writer
+	count int
+	err   error
+}
+
+// Encode will encode a provided value
+//
+// Values are encoded using the form struct tags, fields tagged with omitempty
+// are omitted when empty. Slices of basic values are encoded as repeated
+// keys, nested structs, maps and slices are encoded using bracket notation
+// (address[city], items[0][sku]) matching the Decoder. Values implementing
+// Marshaler or encoding.TextMarshaler are encoded using their own methods.
+func (e *Encoder) Encode(value interface{}) (err error) {
+	e.bw = bufio.NewWriter(e.w)
+	e.count = 0
+	e.err = nil
+	if err = e.encode("", reflect.ValueOf(value)); err != nil {
+		return
+	}
+
+	if e.err != nil {
+		return e.err
+	}
+
+	return e.bw.Flush()
+}
+
+func (e *Encoder) encode(key string, rval reflect.Value) (err error) {
+	for rval.Kind() == reflect.Ptr || rval.Kind() == reflect.Interface {
+		if rval.IsNil() {
+			return
+		}
+
+		if ok, err := e.encodeMarshaler(key, rval); ok {
+			return err
+		}
+
+		rval = rval.Elem()
+	}
+
+	if ok, err := e.encodeMarshaler(key, rval); ok {
+		return err
+	}
+
+	switch rval.Kind() {
+	case reflect.Invalid:
+		return
+	case reflect.Struct:
+		return e.encodeStruct(key, rval)
+	case reflect.Map:
+		return e.encodeMap(key, rval)
+	case reflect.Slice, reflect.Array:
+		if rval.Type().Elem().Kind() == reflect.Uint8 {
+			return e.encodeBasic(key, rval)
+		}
+
+		return e.encodeSlice(key, rval)
+	default:
+		return e.encodeBasic(key, rval)
+	}
+}
+
+// encodeMarshaler will encode a value implementing Marshaler or encoding.TextMarshaler
+func (e *Encoder) encodeMarshaler(key string, rval reflect.Value) (ok bool, err error) {
+	if !rval.CanInterface() {
+		return
+	}
+
+	switch m := rval.Interface().(type) {
+	case Marshaler:
+		return true, m.MarshalForm(func(k, v string) {
+			e.write(childKey(key, k), v)
+		})
+	case encoding.TextMarshaler:
+		var text []byte
+		if text, err = m.MarshalText(); err != nil {
+			return true, err
+		}
+
+		e.write(key, string(text))
+		return true, nil
+	}
+
+	if rval.Kind() != reflect.Ptr && rval.CanAddr() {
+		return e.encodeMarshaler(key, rval.Addr())
+	}
+
+	return
+}
+
+func (e *Encoder) encodeStruct(key string, rval reflect.Value) (err error) {
+	for _, f := range getFields(rval.Type()).list {
+		field := rval.Field(f.index)
+		if f.omitEmpty && isEmpty(field) {
+			continue
+		}
+
+		if t, ok := timeValue(field); ok && len(f.layout) > 0 {
+			// Time is formatted using the layout struct tag
+			e.write(childKey(key, f.name), t.Format(f.layout))
+			continue
+		}
+
+		if err = e.encode(childKey(key, f.name), field); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (e *Encoder) encodeMap(key string, rval reflect.Value) (err error) {
+	keys := make([]string, 0, rval.Len())
+	values := make(map[string]reflect.Value, rval.Len())
+	iter := rval.MapRange()
+	for iter.Next() {
+		var k string
+		if k, err = formatBasic(iter.Key()); err != nil {
+			return
+		}
+
+		keys = append(keys, k)
+		values[k] = iter.Value()
+	}
+
+	// Sort keys so the encoding is deterministic
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err = e.encode(childKey(key, k), values[k]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (e *Encoder) encodeSlice(key string, rval reflect.Value) (err error) {
+	for i := 0; i < rval.Len(); i++ {
+		elem := rval.Index(i)
+		elemKey := key
+		if isNested(elem) {
+			// Nested values are indexed so the fields of each element are grouped
+			elemKey = key + "[" + strconv.Itoa(i) + "]"
+		}
+
+		if err = e.encode(elemKey, elem); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (e *Encoder) encodeBasic(key string, rval reflect.Value) (err error) {
+	if len(key) == 0 {
+		return fmt.Errorf("form: cannot encode %s without a key", rval.Type())
+	}
+
+	var value string
+	if value, err = formatBasic(rval); err != nil {
+		return
+	}
+
+	e.write(key, value)
+	return
+}
+
+func (e *Encoder) write(key, value string) {
+	if e.count > 0 {
+		e.bw.WriteByte('&')
+	}
+
+	e.bw.WriteString(url.QueryEscape(key))
+	e.bw.WriteByte('=')
+	_, e.err = e.bw.WriteString(url.QueryEscape(value))
+	e.count++
+}
+
+// formatBasic will format a basic value as a string
+func formatBasic(rval reflect.Value) (value string, err error) {
+	if rval.Type() == durationType {
+		return time.Duration(rval.Int()).String(), nil
+	}
+
+	switch rval.Kind() {
+	case reflect.String:
+		return rval.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rval.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rval.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rval.Float(), 'f', -1, rval.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rval.Bool()), nil
+	case reflect.Slice:
+		if rval.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rval.Bytes()), nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported type provided, %s is not currently supported", rval.Kind())
+}
+
+// childKey will return the key of a nested value using bracket notation
+func childKey(parent, key string) string {
+	if len(parent) == 0 {
+		return key
+	}
+
+	return parent + "[" + key + "]"
+}
+
+// isNested will return whether or not a value is encoded as multiple keys
+func isNested(rval reflect.Value) bool {
+	for rval.Kind() == reflect.Ptr || rval.Kind() == reflect.Interface {
+		if rval.IsNil() {
+			return false
+		}
+
+		rval = rval.Elem()
+	}
+
+	rtype := rval.Type()
+	switch {
+	case implements(rtype, marshalerType):
+		return true
+	case implements(rtype, textMarshalerType):
+		return false
+	}
+
+	switch rval.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rval.Type().Elem().Kind() != reflect.Uint8 || rval.Kind() == reflect.Map
+	default:
+		return false
+	}
+}
+
+// timeValue will return the time of a time.Time or non-nil *time.Time value
+func timeValue(rval reflect.Value) (t time.Time, ok bool) {
+	if rval.Kind() == reflect.Ptr && !rval.IsNil() {
+		rval = rval.Elem()
+	}
+
+	if rval.Type() != timeType || !rval.CanInterface() {
+		return
+	}
+
+	return rval.Interface().(time.Time), true
+}
+
+func isEmpty(rval reflect.Value) bool {
+	switch rval.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return rval.Len() == 0
+	default:
+		return rval.IsZero()
+	}
+}
+
+// implements will return whether or not a type or a pointer to the type implements an interface
+func implements(rtype, iface reflect.Type) bool {
+	return rtype.Implements(iface) || reflect.PointerTo(rtype).Implements(iface)
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
@@ -0,0 +1,84 @@
+package form
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrMaxDepthExceeded is returned when a key exceeds the maximum nesting depth
+	ErrMaxDepthExceeded = errors.New("form: maximum key depth exceeded")
+	// ErrMaxIndexExceeded is returned when a key exceeds the maximum slice index
+	ErrMaxIndexExceeded = errors.New("form: maximum key index exceeded")
+	// ErrTooManyPairs is returned when a form exceeds the maximum number of key/value pairs
+	ErrTooManyPairs = errors.New("form: maximum number of pairs exceeded")
+	// ErrKeyTooLong is returned when a key exceeds the maximum key length
+	ErrKeyTooLong = errors.New("form: maximum key length exceeded")
+	// ErrValueTooLong is returned when a value exceeds the maximum value length
+	ErrValueTooLong = errors.New("form: maximum value length exceeded")
+	// ErrUnknownKey is the cause of a DecodeError for a key without a matching field when decoding in strict mode
+	ErrUnknownKey = errors.New("unknown key")
+)
+
+// DecodeError is the error of a key/value pair which could not be decoded
+type DecodeError struct {
+	// Key is the key of the pair
+	Key string
+	// Value is the raw value of the pair
+	Value string
+	// Err is the underlying error
+	Err error
+}
+
+// Error will return the error message
+func (d *DecodeError) Error() string {
+	return d.Key + ": " + d.Err.Error()
+}
+
+// Unwrap will return the underlying error
+func (d *DecodeError) Unwrap() error {
+	return d.Err
+}
+
+// DecodeErrors is a list of decode errors
+type DecodeErrors []*DecodeError
+
+// Error will return the error messages joined by a semicolon
+func (d DecodeErrors) Error() string {
+	msgs := make([]string, 0, len(d))
+	for _, err := range d {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap will return the decode errors as an error slice
+func (d DecodeErrors) Unwrap() []error {
+	errs := make([]error, 0, len(d))
+	for _, err := range d {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// err will return the decode errors as an error, nil is returned when the list is empty
+func (d DecodeErrors) err() error {
+	if len(d) == 0 {
+		return nil
+	}
+
+	return d
+}
+
+// isLimitError will return whether or not an error is caused by exceeding a decoding limit
+func isLimitError(err error) bool {
+	for _, limitErr := range [...]error{ErrMaxDepthExceeded, ErrMaxIndexExceeded, ErrTooManyPairs, ErrKeyTooLong, ErrValueTooLong} {
+		if errors.Is(err, limitErr) {
+			return true
+		}
+	}
+
+	return false
+}
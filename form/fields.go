@@ -0,0 +1,70 @@
+package form
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// fieldsCache caches the parsed form fields of struct types
+var fieldsCache sync.Map
+
+// getFields will return the form fields of a struct type
+//
+// Fields are sourced from the reflectio cache, with the tag options (e.g.
+// ",omitempty") parsed from the tag value.
+func getFields(rtype reflect.Type) (fs *fields) {
+	if cached, ok := fieldsCache.Load(rtype); ok {
+		return cached.(*fields)
+	}
+
+	fs = &fields{byName: make(map[string]*field)}
+	for tag, entry := range cache.Get(reflect.New(rtype).Interface(), "form") {
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		var f field
+		f.name = name
+		f.index = entry.FieldIndex()
+		f.omitEmpty = hasOption(opts, "omitempty")
+		f.layout = rtype.Field(f.index).Tag.Get("layout")
+		fs.byName[name] = &f
+		fs.list = append(fs.list, &f)
+	}
+
+	sort.Slice(fs.list, func(i, j int) bool {
+		return fs.list[i].index < fs.list[j].index
+	})
+
+	actual, _ := fieldsCache.LoadOrStore(rtype, fs)
+	return actual.(*fields)
+}
+
+type fields struct {
+	byName map[string]*field
+	// list contains the fields in declaration order
+	list []*field
+}
+
+type field struct {
+	name      string
+	index     int
+	omitEmpty bool
+	// layout is the time layout of the field, set using the layout struct tag
+	layout string
+}
+
+func hasOption(opts, option string) bool {
+	for len(opts) > 0 {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,7 +2,6 @@ package form
 
 import (
 	"io"
-	"strings"
 
 	"github.com/gdbu/reflectio"
 )
@@ -11,7 +10,7 @@ var cache = reflectio.NewCache()
 
 // Unmarshal will parse a form and bind the values to the provided value
 func Unmarshal(query string, value interface{}) (err error) {
-	return BindReader(strings.NewReader(query), value)
+	return NewDecoder(nil).unmarshal(query, value)
 }
 
 // BindReader will parse a query and bind the values to the provided value
@@ -2,13 +2,18 @@ package form
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -18,8 +23,9 @@ var (
 
 func TestBind(t *testing.T) {
 	type testStruct struct {
-		Foo string `form:"foo"`
-		Bar int64  `form:"bar"`
+		Foo   string `form:"foo"`
+		Bar   int64  `form:"bar"`
+		Multi []int  `form:"multi"`
 	}
 
 	u := make(url.Values)
@@ -41,6 +47,90 @@ func TestBind(t *testing.T) {
 	if test.Bar != 1337 {
 		t.Fatalf("invalid value, expected %d and received %d", 1337, test.Bar)
 	}
+
+	if !reflect.DeepEqual(test.Multi, []int{1, 2, 3}) {
+		t.Fatalf("invalid value, expected %v and received %v", []int{1, 2, 3}, test.Multi)
+	}
+}
+
+func TestDecode_nested(t *testing.T) {
+	type address struct {
+		City string `form:"city"`
+		Zip  string `form:"zip"`
+	}
+
+	type item struct {
+		SKU string `form:"sku"`
+		Qty int    `form:"qty"`
+	}
+
+	type testStruct struct {
+		Address  address           `form:"address"`
+		Billing  *address          `form:"billing"`
+		Items    []item            `form:"items"`
+		Tags     []string          `form:"tags"`
+		Meta     map[string]string `form:"meta"`
+		Counts   map[string]int    `form:"counts"`
+		Dotted   string            `form:"dotted.key"`
+		Shipping map[string]address
+	}
+
+	str := "address[city]=Portland&address.zip=97201&billing[city]=Seattle" +
+		"&items[1][sku]=B&items[0][sku]=A&items[0].qty=2&tags[]=a&tags[]=b" +
+		"&meta[foo]=bar&meta[baz]=qux&counts[a]=1&dotted.key=value&unknown[foo]=bar"
+
+	var val testStruct
+	if err := Unmarshal(str, &val); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := testStruct{
+		Address: address{City: "Portland", Zip: "97201"},
+		Billing: &address{City: "Seattle"},
+		Items:   []item{{SKU: "A", Qty: 2}, {SKU: "B"}},
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]string{"foo": "bar", "baz": "qux"},
+		Counts:  map[string]int{"a": 1},
+		Dotted:  "value",
+	}
+
+	if !reflect.DeepEqual(val, expected) {
+		t.Fatalf("invalid value, expected %+v and received %+v", expected, val)
+	}
+}
+
+func TestDecode_limits(t *testing.T) {
+	type testStruct struct {
+		Items []struct {
+			Tags []string `form:"tags"`
+		} `form:"items"`
+		Multi []string `form:"multi"`
+	}
+
+	tcs := []struct {
+		name     string
+		query    string
+		maxDepth int
+		maxIndex int
+		err      error
+	}{
+		{name: "within limits", query: "items[2][tags][1]=a&multi=a&multi=b", maxDepth: 3, maxIndex: 2},
+		{name: "depth", query: "items[0][tags][0]=a", maxDepth: 2, maxIndex: 2, err: ErrMaxDepthExceeded},
+		{name: "index", query: "items[3][tags][0]=a", maxDepth: 3, maxIndex: 2, err: ErrMaxIndexExceeded},
+		{name: "repeated", query: "multi=a&multi=b&multi=c&multi=d", maxDepth: 3, maxIndex: 2, err: ErrMaxIndexExceeded},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var val testStruct
+			dec := NewDecoder(strings.NewReader(tc.query))
+			dec.SetMaxDepth(tc.maxDepth)
+			dec.SetMaxIndex(tc.maxIndex)
+			if err := dec.Decode(&val); !errors.Is(err, tc.err) {
+				t.Fatalf("invalid error, expected %v and received %v", tc.err, err)
+			}
+		})
+	}
 }
 
 func TestDecode(t *testing.T) {
@@ -203,3 +293,431 @@ func (r *ronly) Read(p []byte) (n int, err error) {
 }
 
 var _ io.Reader = &ronly{}
+
+func TestMarshal(t *testing.T) {
+	type address struct {
+		City string `form:"city"`
+		Zip  string `form:"zip,omitempty"`
+	}
+
+	type item struct {
+		SKU string `form:"sku"`
+		Qty int    `form:"qty"`
+	}
+
+	type testStruct struct {
+		Name    string            `form:"name"`
+		Age     int               `form:"age,omitempty"`
+		Score   float64           `form:"score"`
+		Active  bool              `form:"active"`
+		Address address           `form:"address"`
+		Billing *address          `form:"billing"`
+		Items   []item            `form:"items"`
+		Tags    []string          `form:"tags"`
+		Meta    map[string]string `form:"meta"`
+		Custom  testMarshaler     `form:"custom"`
+		Ignored string
+		Skipped string `form:"-"`
+	}
+
+	value := testStruct{
+		Name:    "John Doe",
+		Score:   1.5,
+		Address: address{City: "Portland"},
+		Items:   []item{{SKU: "A", Qty: 2}, {SKU: "B"}},
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]string{"z": "1", "a": "2"},
+		Custom:  testMarshaler{foo: "bar"},
+		Ignored: "ignored",
+		Skipped: "skipped",
+	}
+
+	query, err := Marshal(&value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "name=John+Doe&score=1.5&active=false&address%5Bcity%5D=Portland" +
+		"&items%5B0%5D%5Bsku%5D=A&items%5B0%5D%5Bqty%5D=2&items%5B1%5D%5Bsku%5D=B&items%5B1%5D%5Bqty%5D=0" +
+		"&tags=a&tags=b&meta%5Ba%5D=2&meta%5Bz%5D=1&custom%5Bfoo%5D=bar"
+	if query != expected {
+		t.Fatalf("invalid query, expected\n%s\nand received\n%s", expected, query)
+	}
+
+	var decoded testStruct
+	if err = Unmarshal(query, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	value.Custom = testMarshaler{}
+	value.Ignored = ""
+	value.Skipped = ""
+	if !reflect.DeepEqual(decoded, value) {
+		t.Fatalf("invalid round trip, expected %+v and received %+v", value, decoded)
+	}
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(map[string][]string{"foo": {"1", "2"}, "bar": {"3"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "bar=3&foo=1&foo=2"; buf.String() != expected {
+		t.Fatalf("invalid query, expected %q and received %q", expected, buf.String())
+	}
+
+	if err := NewEncoder(&buf).Encode("foo"); err == nil {
+		t.Fatal("expected an error encoding a value without a key")
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	test := testStruct{Foo: "hello world!", Bar: 1337}
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(&test); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+}
+
+type testMarshaler struct {
+	foo string
+}
+
+func (t testMarshaler) MarshalForm(add func(key, value string)) error {
+	add("foo", t.foo)
+	return nil
+}
+
+func TestDecode_types(t *testing.T) {
+	type testStruct struct {
+		Start    time.Time        `form:"start" layout:"2006-01-02"`
+		End      *time.Time       `form:"end" layout:"2006-01-02"`
+		Created  time.Time        `form:"created"`
+		Timeout  time.Duration    `form:"timeout"`
+		IP       net.IP           `form:"ip"`
+		Age      *int             `form:"age"`
+		Nickname *string          `form:"nickname"`
+		Custom   testCustomType   `form:"custom"`
+		Customs  []testCustomType `form:"customs"`
+	}
+
+	RegisterConverter(func(value string) (testCustomType, error) {
+		return testCustomType(strings.ToUpper(value)), nil
+	})
+
+	str := "start=2024-01-02&end=2024-02-03&created=2024-01-02T15:04:05Z&timeout=1m30s" +
+		"&ip=127.0.0.1&age=&nickname=joe&custom=foo&customs=a&customs=b"
+
+	var val testStruct
+	if err := Unmarshal(str, &val); err != nil {
+		t.Fatal(err)
+	}
+
+	nickname := "joe"
+	expected := testStruct{
+		Start:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		End:      &[]time.Time{time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)}[0],
+		Created:  time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Timeout:  90 * time.Second,
+		IP:       net.ParseIP("127.0.0.1"),
+		Nickname: &nickname,
+		Custom:   "FOO",
+		Customs:  []testCustomType{"A", "B"},
+	}
+
+	if !reflect.DeepEqual(val, expected) {
+		t.Fatalf("invalid value, expected %+v and received %+v", expected, val)
+	}
+
+	query, err := Marshal(&val)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(query, "start=2024-01-02&end=2024-02-03&created=2024-01-02T15%3A04%3A05Z&timeout=1m30s") {
+		t.Fatalf("invalid time encoding: %s", query)
+	}
+
+	if err = Unmarshal("start=01/02/2024", &val); err == nil {
+		t.Fatal("expected an error for a time not matching the layout")
+	}
+}
+
+type testCustomType string
+
+func TestDecode_errors(t *testing.T) {
+	type testStruct struct {
+		Name  string `form:"name"`
+		Age   int    `form:"age"`
+		Score uint   `form:"score"`
+	}
+
+	var val testStruct
+	err := Unmarshal("name=foo&age=abc&score=-1&unknown=bar", &val)
+
+	var errs DecodeErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected DecodeErrors, received %v", err)
+	}
+
+	if len(errs) != 2 || errs[0].Key != "age" || errs[0].Value != "abc" || errs[1].Key != "score" || errs[1].Value != "-1" {
+		t.Fatalf("invalid errors: %v", errs)
+	}
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Fatalf("expected the cause to be a *strconv.NumError, received %v", errs[0].Err)
+	}
+
+	if val.Name != "foo" {
+		t.Fatalf("expected valid values to be set, received %+v", val)
+	}
+
+	dec := NewDecoder(strings.NewReader("name=foo&unknown=bar&nested[foo]=bar"))
+	dec.SetStrict(true)
+	err = dec.Decode(&val)
+	if !errors.As(err, &errs) || len(errs) != 2 || !errors.Is(errs[0], ErrUnknownKey) || errs[1].Key != "nested[foo]" {
+		t.Fatalf("expected unknown key errors, received %v", err)
+	}
+}
+
+func TestDecode_abuseLimits(t *testing.T) {
+	type testStruct struct {
+		Name string `form:"name"`
+	}
+
+	tcs := []struct {
+		name  string
+		query string
+		set   func(d *Decoder)
+		err   error
+	}{
+		{name: "pairs", query: "a=1&b=2&c=3", set: func(d *Decoder) { d.SetMaxPairs(2) }, err: ErrTooManyPairs},
+		{name: "key length", query: "name=foo&" + strings.Repeat("a", 11) + "=1", set: func(d *Decoder) { d.SetMaxKeyLength(10) }, err: ErrKeyTooLong},
+		{name: "value length", query: "name=" + strings.Repeat("a", 11), set: func(d *Decoder) { d.SetMaxValueLength(10) }, err: ErrValueTooLong},
+		{name: "within limits", query: "name=" + strings.Repeat("a", 10), set: func(d *Decoder) { d.SetMaxValueLength(10) }},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var val testStruct
+			dec := NewDecoder(strings.NewReader(tc.query))
+			tc.set(dec)
+			if err := dec.Decode(&val); !errors.Is(err, tc.err) {
+				t.Fatalf("invalid error, expected %v and received %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMultipartDecoder_Decode(t *testing.T) {
+	type testStruct struct {
+		Name        string   `form:"name"`
+		Tags        []string `form:"tags"`
+		Avatar      *File    `form:"avatar"`
+		Attachments []*File  `form:"attachments"`
+	}
+
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 64)...)
+	body, boundary := newTestMultipart(t, map[string]string{"name": "John Doe", "tags": "a"}, []testFile{
+		{field: "avatar", filename: "avatar.png", body: png},
+		{field: "attachments", filename: "a.txt", body: []byte("hello world")},
+		{field: "attachments", filename: "b.txt", body: bytes.Repeat([]byte("b"), 100)},
+	})
+
+	var val testStruct
+	dec := NewMultipartDecoder(bytes.NewReader(body), boundary)
+	dec.SetMemoryLimit(32)
+	defer dec.RemoveAll()
+	if err := dec.Decode(&val); err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Name != "John Doe" || !reflect.DeepEqual(val.Tags, []string{"a"}) {
+		t.Fatalf("invalid values: %+v", val)
+	}
+
+	if val.Avatar == nil || val.Avatar.Filename != "avatar.png" || val.Avatar.ContentType != "image/png" || val.Avatar.Size != int64(len(png)) {
+		t.Fatalf("invalid avatar: %+v", val.Avatar)
+	}
+
+	if len(val.Attachments) != 2 || val.Attachments[1].file == nil {
+		t.Fatalf("expected the second attachment to be buffered to a temporary file: %+v", val.Attachments)
+	}
+
+	for i, expected := range []string{"hello world", strings.Repeat("b", 100)} {
+		bs, err := io.ReadAll(val.Attachments[i].Open())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(bs) != expected {
+			t.Fatalf("invalid attachment contents, expected %q and received %q", expected, string(bs))
+		}
+	}
+}
+
+func TestMultipartDecoder_limits(t *testing.T) {
+	type testStruct struct {
+		Name string `form:"name"`
+		File *File  `form:"file"`
+	}
+
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 64)...)
+	tcs := []struct {
+		name string
+		set  func(d *MultipartDecoder)
+		err  error
+	}{
+		{name: "file size", set: func(d *MultipartDecoder) { d.SetMaxFileSize(16) }, err: ErrFileTooLarge},
+		{name: "total size", set: func(d *MultipartDecoder) { d.SetMaxTotalSize(64) }, err: ErrMultipartTooLarge},
+		{name: "allowed type", set: func(d *MultipartDecoder) { d.SetAllowedTypes("image/*") }},
+		{name: "disallowed type", set: func(d *MultipartDecoder) { d.SetAllowedTypes("text/plain") }, err: ErrFileTypeNotAllowed},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			body, boundary := newTestMultipart(t, map[string]string{"name": "foo"}, []testFile{{field: "file", filename: "file.png", body: png}})
+
+			var val testStruct
+			dec := NewMultipartDecoder(bytes.NewReader(body), boundary)
+			tc.set(dec)
+			defer dec.RemoveAll()
+			if err := dec.Decode(&val); !errors.Is(err, tc.err) {
+				t.Fatalf("invalid error, expected %v and received %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMultipartDecoder_SetFileSink(t *testing.T) {
+	type testStruct struct {
+		Name string `form:"name"`
+		File *File  `form:"file"`
+	}
+
+	body, boundary := newTestMultipart(t, map[string]string{"name": "foo"}, []testFile{{field: "file", filename: "file.txt", body: []byte("hello world")}})
+
+	var (
+		val  testStruct
+		sunk bytes.Buffer
+	)
+
+	dec := NewMultipartDecoder(bytes.NewReader(body), boundary)
+	dec.SetFileSink(func(part *FilePart) (err error) {
+		if part.Field != "file" || part.Filename != "file.txt" || part.ContentType != "text/plain; charset=utf-8" {
+			t.Fatalf("invalid part: %+v", part)
+		}
+
+		_, err = io.Copy(&sunk, part)
+		return
+	})
+
+	if err := dec.Decode(&val); err != nil {
+		t.Fatal(err)
+	}
+
+	if sunk.String() != "hello world" || val.File != nil || val.Name != "foo" {
+		t.Fatalf("invalid result, sunk %q into %+v", sunk.String(), val)
+	}
+}
+
+type testFile struct {
+	field    string
+	filename string
+	body     []byte
+}
+
+func newTestMultipart(t *testing.T, values map[string]string, files []testFile) (body []byte, boundary string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range values {
+		if err := w.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, f := range files {
+		fw, err := w.CreateFormFile(f.field, f.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = fw.Write(f.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes(), w.Boundary()
+}
+
+func TestDecode_reader(t *testing.T) {
+	type testStruct struct {
+		Token string `form:"token"`
+		Long  string `form:"long"`
+		Empty string `form:"empty"`
+	}
+
+	long := strings.Repeat("a", 10000)
+	str := "token=YWJj%3D%3D&&long=" + long + "&empty=&=&token2=a=b"
+	for _, rdr := range []io.Reader{strings.NewReader(str), &ronly{strings.NewReader(str)}} {
+		var val testStruct
+		if err := BindReader(rdr, &val); err != nil {
+			t.Fatal(err)
+		}
+
+		if val.Token != "YWJj==" || val.Long != long {
+			t.Fatalf("invalid value: %+v", val)
+		}
+	}
+
+	var val testStruct
+	if err := Unmarshal("token=a=b", &val); err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Token != "a=b" {
+		t.Fatalf("invalid value, expected %q and received %q", "a=b", val.Token)
+	}
+
+	dec := NewDecoder(&ronly{strings.NewReader("long=" + long)})
+	dec.SetMaxValueLength(5000)
+	if err := dec.Decode(&val); !errors.Is(err, ErrValueTooLong) {
+		t.Fatalf("expected ErrValueTooLong, received %v", err)
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	var test testStruct
+	for i := 0; i < b.N; i++ {
+		if err := Unmarshal(testQueryString, &test); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+}
+
+func BenchmarkBindReader_stream(b *testing.B) {
+	var test testStruct
+	rdr := strings.NewReader(testQueryString)
+	for i := 0; i < b.N; i++ {
+		if err := BindReader(&ronly{rdr}, &test); err != nil {
+			b.Fatal(err)
+		}
+
+		if _, err := rdr.Seek(0, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+}
@@ -0,0 +1,425 @@
+package form
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	// DefaultMaxFileSize is the default maximum size of a multipart file
+	DefaultMaxFileSize = 32 << 20
+	// DefaultMaxMultipartSize is the default maximum total size of a multipart form
+	DefaultMaxMultipartSize = 64 << 20
+	// DefaultFileMemoryLimit is the default size at which files are spilled to a temporary file
+	DefaultFileMemoryLimit = 1 << 20
+
+	// sniffLength is the number of bytes used to detect the content type of a file
+	sniffLength = 512
+)
+
+var (
+	// ErrFileTooLarge is returned when a multipart file exceeds the maximum file size
+	ErrFileTooLarge = errors.New("form: maximum file size exceeded")
+	// ErrMultipartTooLarge is returned when a multipart form exceeds the maximum total size
+	ErrMultipartTooLarge = errors.New("form: maximum multipart size exceeded")
+	// ErrFileTypeNotAllowed is the cause of a DecodeError for a file with a content type which is not allowed
+	ErrFileTypeNotAllowed = errors.New("file type is not allowed")
+)
+
+var (
+	fileType      = reflect.TypeOf(File{})
+	filePtrType   = reflect.TypeOf(&File{})
+	fileSliceType = reflect.TypeOf([]*File{})
+)
+
+// FileSink is called for each file part, the part must be consumed before returning
+type FileSink func(part *FilePart) error
+
+// FilePart is a file part of a multipart form which is being streamed
+type FilePart struct {
+	// Field is the form field name of the part
+	Field string
+	// Filename is the filename provided by the client
+	Filename string
+	// ContentType is the content type detected from the file contents
+	ContentType string
+	// Header is the header of the part
+	Header textproto.MIMEHeader
+
+	io.Reader
+}
+
+// File is a file part of a multipart form, buffered in memory or within a temporary file
+type File struct {
+	// Filename is the filename provided by the client
+	Filename string
+	// ContentType is the content type detected from the file contents
+	ContentType string
+	// Header is the header of the part
+	Header textproto.MIMEHeader
+	// Size is the size of the file in bytes
+	Size int64
+
+	mem  []byte
+	file *os.File
+}
+
+// Open will return a reader of the file contents, positioned at the start of the file
+func (f *File) Open() io.ReadSeeker {
+	if f.file != nil {
+		return io.NewSectionReader(f.file, 0, f.Size)
+	}
+
+	return bytes.NewReader(f.mem)
+}
+
+// Remove will remove the temporary file holding the file contents, if any
+func (f *File) Remove() (err error) {
+	if f.file == nil {
+		return
+	}
+
+	f.file.Close()
+	err = os.Remove(f.file.Name())
+	f.file = nil
+	return
+}
+
+// NewMultipartDecoder will initialize a new multipart decoder for the provided boundary
+//
+// The boundary can be parsed from the Content-Type header using ParseBoundary.
+func NewMultipartDecoder(r io.Reader, boundary string) *MultipartDecoder {
+	var d MultipartDecoder
+	d.r = r
+	d.boundary = boundary
+	d.opts.maxDepth = DefaultMaxDepth
+	d.opts.maxIndex = DefaultMaxIndex
+	d.opts.maxPairs = DefaultMaxPairs
+	d.opts.maxKeyLength = DefaultMaxKeyLength
+	d.opts.maxValueLength = DefaultMaxValueLength
+	d.maxFileSize = DefaultMaxFileSize
+	d.maxTotalSize = DefaultMaxMultipartSize
+	d.memoryLimit = DefaultFileMemoryLimit
+	return &d
+}
+
+// ParseBoundary will parse the boundary of a multipart/form-data Content-Type header
+func ParseBoundary(contentType string) (boundary string, err error) {
+	var (
+		mediaType string
+		params    map[string]string
+	)
+
+	if mediaType, params, err = mime.ParseMediaType(contentType); err != nil {
+		return
+	}
+
+	if mediaType != "multipart/form-data" {
+		return "", fmt.Errorf("form: invalid multipart content type %q", mediaType)
+	}
+
+	if boundary = params["boundary"]; len(boundary) == 0 {
+		return "", errors.New("form: multipart boundary is missing")
+	}
+
+	return
+}
+
+// MultipartDecoder will decode a multipart/form-data body
+type MultipartDecoder struct {
+	r        io.Reader
+	boundary string
+	opts     options
+
+	maxFileSize  int64
+	maxTotalSize int64
+	memoryLimit  int64
+	allowedTypes []string
+	sink         FileSink
+
+	files []*File
+	errs  DecodeErrors
+}
+
+// SetMaxFileSize will set the maximum size of a file, files exceeding it return ErrFileTooLarge
+func (d *MultipartDecoder) SetMaxFileSize(n int64) {
+	d.maxFileSize = n
+}
+
+// SetMaxTotalSize will set the maximum total size of the form, forms exceeding it return ErrMultipartTooLarge
+func (d *MultipartDecoder) SetMaxTotalSize(n int64) {
+	d.maxTotalSize = n
+}
+
+// SetMemoryLimit will set the size at which files are spilled to a temporary file
+func (d *MultipartDecoder) SetMemoryLimit(n int64) {
+	d.memoryLimit = n
+}
+
+// SetAllowedTypes will set the content types files are allowed to have, such as "image/png" or "image/*"
+//
+// Content types are detected from the file contents using http.DetectContentType,
+// the Content-Type provided by the client is ignored. Files of other types
+// result in a DecodeError caused by ErrFileTypeNotAllowed.
+func (d *MultipartDecoder) SetAllowedTypes(types ...string) {
+	d.allowedTypes = types
+}
+
+// SetFileSink will set a func which file parts are streamed to, rather than being buffered into File fields
+func (d *MultipartDecoder) SetFileSink(sink FileSink) {
+	d.sink = sink
+}
+
+// SetStrict will set whether or not parts without a matching field result in a DecodeError caused by ErrUnknownKey
+func (d *MultipartDecoder) SetStrict(strict bool) {
+	d.opts.strict = strict
+}
+
+// Decode will decode a provided value
+//
+// Text parts are decoded in the same manner as Decoder.Decode. File parts
+// are set to File, *File and []*File fields, buffered in memory up to the
+// memory limit and to a temporary file beyond it (use RemoveAll to remove
+// them). When a file sink is set, file parts are streamed to the sink instead.
+func (d *MultipartDecoder) Decode(value interface{}) (err error) {
+	u, ok := value.(Unmarshaler)
+	if !ok {
+		u = newMapUnmarshaler(value, &d.opts)
+	}
+
+	rval := reflect.ValueOf(value)
+	if rval.Kind() == reflect.Ptr {
+		rval = rval.Elem()
+	}
+
+	d.errs = nil
+	mr := multipart.NewReader(&multipartReader{r: d.r, n: d.maxTotalSize}, d.boundary)
+	for pairs := 1; ; pairs++ {
+		var part *multipart.Part
+		if part, err = mr.NextPart(); err == io.EOF {
+			break
+		} else if err != nil {
+			return
+		}
+
+		if pairs > d.opts.maxPairs {
+			return fmt.Errorf("%w: maximum is %d", ErrTooManyPairs, d.opts.maxPairs)
+		}
+
+		if len(part.FileName()) == 0 {
+			err = d.decodeValue(u, part)
+		} else {
+			err = d.decodeFile(rval, part)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	return d.errs.err()
+}
+
+// RemoveAll will remove the temporary files of all decoded files
+func (d *MultipartDecoder) RemoveAll() (err error) {
+	for _, f := range d.files {
+		if rerr := f.Remove(); rerr != nil {
+			err = rerr
+		}
+	}
+
+	d.files = d.files[:0]
+	return
+}
+
+func (d *MultipartDecoder) decodeValue(u Unmarshaler, part *multipart.Part) (err error) {
+	key := part.FormName()
+	if len(key) > d.opts.maxKeyLength {
+		return fmt.Errorf("%w: maximum is %d", ErrKeyTooLong, d.opts.maxKeyLength)
+	}
+
+	var bs []byte
+	if bs, err = io.ReadAll(io.LimitReader(part, int64(d.opts.maxValueLength)+1)); err != nil {
+		return
+	}
+
+	if len(bs) > d.opts.maxValueLength {
+		return fmt.Errorf("%w: maximum is %d", ErrValueTooLong, d.opts.maxValueLength)
+	}
+
+	value := string(bs)
+	if err = u.UnmarshalForm(key, value); err == nil || isLimitError(err) {
+		return
+	}
+
+	d.errs = append(d.errs, &DecodeError{Key: key, Value: value, Err: err})
+	return nil
+}
+
+func (d *MultipartDecoder) decodeFile(rval reflect.Value, part *multipart.Part) (err error) {
+	var fp FilePart
+	fp.Field = part.FormName()
+	fp.Filename = part.FileName()
+	fp.Header = part.Header
+
+	// Detect the content type from the start of the file
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(part, head)
+	switch err {
+	case nil, io.EOF, io.ErrUnexpectedEOF:
+		// File may be smaller than the sniff length
+		err = nil
+	default:
+		return
+	}
+
+	head = head[:n]
+	fp.ContentType = http.DetectContentType(head)
+	if !d.isAllowed(fp.ContentType) {
+		d.errs = append(d.errs, &DecodeError{Key: fp.Field, Value: fp.Filename, Err: fmt.Errorf("%w: %s", ErrFileTypeNotAllowed, fp.ContentType)})
+		return
+	}
+
+	fp.Reader = &fileReader{r: io.MultiReader(bytes.NewReader(head), part), n: d.maxFileSize}
+	if d.sink != nil {
+		return d.sink(&fp)
+	}
+
+	target, ok := fileField(rval, fp.Field)
+	if !ok {
+		if d.opts.strict {
+			d.errs = append(d.errs, &DecodeError{Key: fp.Field, Value: fp.Filename, Err: ErrUnknownKey})
+		}
+
+		return
+	}
+
+	var f *File
+	if f, err = d.bufferFile(&fp); err != nil {
+		return
+	}
+
+	switch target.Type() {
+	case fileType:
+		target.Set(reflect.ValueOf(f).Elem())
+	case filePtrType:
+		target.Set(reflect.ValueOf(f))
+	case fileSliceType:
+		target.Set(reflect.Append(target, reflect.ValueOf(f)))
+	}
+
+	return
+}
+
+func (d *MultipartDecoder) bufferFile(fp *FilePart) (f *File, err error) {
+	f = &File{Filename: fp.Filename, ContentType: fp.ContentType, Header: fp.Header}
+	d.files = append(d.files, f)
+
+	var buf bytes.Buffer
+	if f.Size, err = io.CopyN(&buf, fp, d.memoryLimit+1); err == io.EOF {
+		// File fits within memory limit
+		f.mem = buf.Bytes()
+		return f, nil
+	} else if err != nil {
+		return
+	}
+
+	// File exceeds memory limit, spill to a temporary file
+	if f.file, err = os.CreateTemp("", "form-file-*"); err != nil {
+		return
+	}
+
+	if _, err = buf.WriteTo(f.file); err != nil {
+		return
+	}
+
+	var rest int64
+	if rest, err = io.Copy(f.file, fp); err != nil {
+		return
+	}
+
+	f.Size += rest
+	return
+}
+
+func (d *MultipartDecoder) isAllowed(contentType string) bool {
+	if len(d.allowedTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	for _, allowed := range d.allowedTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+
+			continue
+		}
+
+		if mediaType == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileField will return the File, *File or []*File field of a struct for the provided key
+func fileField(rval reflect.Value, key string) (field reflect.Value, ok bool) {
+	if rval.Kind() != reflect.Struct {
+		return
+	}
+
+	f, ok := getFields(rval.Type()).byName[key]
+	if !ok {
+		return
+	}
+
+	field = rval.Field(f.index)
+	switch field.Type() {
+	case fileType, filePtrType, fileSliceType:
+		return field, true
+	default:
+		return field, false
+	}
+}
+
+// fileReader reads up to n bytes, returning ErrFileTooLarge when the file has more data
+type fileReader struct {
+	r io.Reader
+	n int64
+}
+
+func (f *fileReader) Read(p []byte) (n int, err error) {
+	if n, err = f.r.Read(p); int64(n) > f.n {
+		return int(f.n), fmt.Errorf("%w: maximum is %d", ErrFileTooLarge, f.n)
+	}
+
+	f.n -= int64(n)
+	return
+}
+
+// multipartReader reads up to n bytes, returning ErrMultipartTooLarge when the form has more data
+type multipartReader struct {
+	r io.Reader
+	n int64
+}
+
+func (m *multipartReader) Read(p []byte) (n int, err error) {
+	if n, err = m.r.Read(p); int64(n) > m.n {
+		return int(m.n), ErrMultipartTooLarge
+	}
+
+	m.n -= int64(n)
+	return
+}
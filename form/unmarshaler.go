@@ -1,9 +1,10 @@
 package form
 
 import (
+	"fmt"
 	"reflect"
-
-	"github.com/gdbu/reflectio"
+	"strconv"
+	"strings"
 )
 
 // Unmarshaler is an decoding helper interface
@@ -11,21 +12,196 @@ type Unmarshaler interface {
 	UnmarshalForm(key, value string) error
 }
 
-func newMapUnmarshaler(value interface{}) *mapUnmarshaler {
+func newMapUnmarshaler(value interface{}, opts *options) *mapUnmarshaler {
 	var m mapUnmarshaler
 	if m.rval = reflect.ValueOf(value); m.rval.Kind() == reflect.Ptr {
 		m.rval = m.rval.Elem()
 	}
 
-	m.m = cache.Get(value, "form")
+	m.opts = opts
 	return &m
 }
 
 type mapUnmarshaler struct {
-	m    reflectio.Map
 	rval reflect.Value
+	opts *options
 }
 
 func (m *mapUnmarshaler) UnmarshalForm(key, value string) (err error) {
-	return m.m.SetValueAsString(m.rval, key, value)
+	if m.rval.Kind() != reflect.Struct {
+		return m.unknownKey()
+	}
+
+	path := []string{key}
+	if _, ok := getFields(m.rval.Type()).byName[key]; !ok {
+		// Key does not match a field exactly, attempt to parse it as a nested key
+		path = splitKey(key)
+	}
+
+	if depth := len(path) - 1; depth > m.opts.maxDepth {
+		return fmt.Errorf("%w: %q has a depth of %d, maximum is %d", ErrMaxDepthExceeded, key, depth, m.opts.maxDepth)
+	}
+
+	return m.set(m.rval, path, value, "")
+}
+
+// set will set the value at the provided path within the target, layout is the time layout of the nearest field
+func (m *mapUnmarshaler) set(target reflect.Value, path []string, value, layout string) (err error) {
+	if len(path) == 0 {
+		return m.setLeaf(target, value, layout)
+	}
+
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
+		target = target.Elem()
+	}
+
+	switch target.Kind() {
+	case reflect.Struct:
+		f, ok := getFields(target.Type()).byName[path[0]]
+		if !ok {
+			return m.unknownKey()
+		}
+
+		return m.set(target.Field(f.index), path[1:], value, f.layout)
+	case reflect.Map:
+		return m.setMapIndex(target, path, value, layout)
+	case reflect.Slice:
+		return m.setSliceIndex(target, path, value, layout)
+	case reflect.Array:
+		var index int
+		if index, err = m.parseIndex(path[0]); err != nil {
+			return
+		}
+
+		if index >= target.Len() {
+			return fmt.Errorf("%w: index %d is out of range for %s", ErrMaxIndexExceeded, index, target.Type())
+		}
+
+		return m.set(target.Index(index), path[1:], value, layout)
+	default:
+		// Path continues past a value which cannot contain nested values
+		return m.unknownKey()
+	}
+}
+
+// unknownKey will return ErrUnknownKey in strict mode, otherwise unknown keys are ignored
+func (m *mapUnmarshaler) unknownKey() error {
+	if m.opts.strict {
+		return ErrUnknownKey
+	}
+
+	return nil
+}
+
+// setLeaf will set the value to the target, appending to slices
+func (m *mapUnmarshaler) setLeaf(target reflect.Value, value, layout string) (err error) {
+	if target.Kind() != reflect.Slice || isScalar(target.Type()) {
+		return setValueAsString(target, value, layout)
+	}
+
+	return m.setSliceIndex(target, []string{""}, value, layout)
+}
+
+func (m *mapUnmarshaler) setMapIndex(target reflect.Value, path []string, value, layout string) (err error) {
+	rtype := target.Type()
+	key := reflect.New(rtype.Key()).Elem()
+	if err = SetValueAsString(key, path[0]); err != nil {
+		return
+	}
+
+	if target.IsNil() {
+		target.Set(reflect.MakeMap(rtype))
+	}
+
+	// Map values are not addressable, set a copy of the existing value and store it
+	elem := reflect.New(rtype.Elem()).Elem()
+	if existing := target.MapIndex(key); existing.IsValid() {
+		elem.Set(existing)
+	}
+
+	if err = m.set(elem, path[1:], value, layout); err != nil {
+		return
+	}
+
+	target.SetMapIndex(key, elem)
+	return
+}
+
+func (m *mapUnmarshaler) setSliceIndex(target reflect.Value, path []string, value, layout string) (err error) {
+	index := target.Len()
+	if len(path[0]) > 0 {
+		// Index is provided, otherwise the value is appended
+		if index, err = m.parseIndex(path[0]); err != nil {
+			return
+		}
+	} else if index > m.opts.maxIndex {
+		return fmt.Errorf("%w: slice exceeds the maximum length of %d", ErrMaxIndexExceeded, m.opts.maxIndex+1)
+	}
+
+	if index >= target.Len() {
+		grown := reflect.MakeSlice(target.Type(), index+1, index+1)
+		reflect.Copy(grown, target)
+		target.Set(grown)
+	}
+
+	return m.set(target.Index(index), path[1:], value, layout)
+}
+
+func (m *mapUnmarshaler) parseIndex(str string) (index int, err error) {
+	if index, err = strconv.Atoi(str); err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid index %q", str)
+	}
+
+	if index > m.opts.maxIndex {
+		return 0, fmt.Errorf("%w: index %d is greater than the maximum of %d", ErrMaxIndexExceeded, index, m.opts.maxIndex)
+	}
+
+	return
+}
+
+// splitKey will split a nested key using bracket (a[b][0]) and dot (a.b.0) notation
+//
+// Empty brackets (a[]) result in an empty segment, which appends to a slice.
+// Malformed keys are returned as a single segment.
+func splitKey(key string) (path []string) {
+	if strings.IndexAny(key, "[.") == -1 {
+		return []string{key}
+	}
+
+	rest := key
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return []string{key}
+			}
+
+			path = append(path, rest[1:end])
+			rest = rest[end+1:]
+		case '.':
+			rest = rest[1:]
+			if len(rest) == 0 {
+				return []string{key}
+			}
+		default:
+			end := strings.IndexAny(rest, "[.")
+			if end == -1 {
+				end = len(rest)
+			}
+
+			path = append(path, rest[:end])
+			rest = rest[end:]
+		}
+	}
+
+	if len(path) == 0 || len(path[0]) == 0 {
+		return []string{key}
+	}
+
+	return
 }
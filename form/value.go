@@ -0,0 +1,191 @@
+package form
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gdbu/reflectio"
+)
+
+var (
+	// converters are the registered custom converters, keyed by type
+	converters sync.Map
+
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	setterType          = reflect.TypeOf((*reflectio.Setter)(nil)).Elem()
+)
+
+// RegisterConverter will register a func which converts string values to the type T
+//
+// Converters take precedence over the built-in conversions, including
+// reflectio.Setter and encoding.TextUnmarshaler implementations.
+func RegisterConverter[T any](fn func(value string) (T, error)) {
+	converters.Store(reflect.TypeOf((*T)(nil)).Elem(), func(value string) (out reflect.Value, err error) {
+		var v T
+		if v, err = fn(value); err != nil {
+			return
+		}
+
+		return reflect.ValueOf(&v).Elem(), nil
+	})
+}
+
+// SetValueAsString will set a string value to the provided target, converting it to the target's type
+//
+// Supported targets are basic kinds, time.Duration, time.Time (RFC 3339),
+// types with a registered converter, and types implementing reflectio.Setter
+// or encoding.TextUnmarshaler. Pointer targets are allocated, an empty value
+// sets a pointer target to nil.
+func SetValueAsString(target reflect.Value, value string) (err error) {
+	return setValueAsString(target, value, "")
+}
+
+// setValueAsString will set a string value to the provided target, time values are parsed using the layout when set
+func setValueAsString(target reflect.Value, value, layout string) (err error) {
+	if target.Kind() == reflect.Ptr {
+		if len(value) == 0 {
+			target.Set(reflect.Zero(target.Type()))
+			return
+		}
+
+		elem := reflect.New(target.Type().Elem())
+		if err = setValueAsString(elem.Elem(), value, layout); err != nil {
+			return
+		}
+
+		target.Set(elem)
+		return
+	}
+
+	if convert, ok := converters.Load(target.Type()); ok {
+		var converted reflect.Value
+		if converted, err = convert.(func(string) (reflect.Value, error))(value); err != nil {
+			return
+		}
+
+		target.Set(converted)
+		return
+	}
+
+	if target.Type() == timeType && len(layout) > 0 {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err != nil {
+			return
+		}
+
+		target.Set(reflect.ValueOf(t))
+		return
+	}
+
+	if setter, ok := asSetter(target); ok {
+		return setter.SetValueAsString(value)
+	}
+
+	if u, ok := asTextUnmarshaler(target); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	if target.Type() == durationType {
+		var d time.Duration
+		if d, err = time.ParseDuration(value); err != nil {
+			return
+		}
+
+		target.SetInt(int64(d))
+		return
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i64 int64
+		if i64, err = strconv.ParseInt(value, 10, target.Type().Bits()); err != nil {
+			return
+		}
+
+		target.SetInt(i64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u64 uint64
+		if u64, err = strconv.ParseUint(value, 10, target.Type().Bits()); err != nil {
+			return
+		}
+
+		target.SetUint(u64)
+	case reflect.Float32, reflect.Float64:
+		var f64 float64
+		if f64, err = strconv.ParseFloat(value, target.Type().Bits()); err != nil {
+			return
+		}
+
+		target.SetFloat(f64)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(value); err != nil {
+			return
+		}
+
+		target.SetBool(b)
+	case reflect.Slice:
+		if target.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type provided, %s is not currently supported", target.Type())
+		}
+
+		target.SetBytes([]byte(value))
+	default:
+		err = fmt.Errorf("unsupported type provided, %s is not currently supported", target.Type())
+	}
+
+	return
+}
+
+// isScalar will return whether or not a type is set from a single string value
+func isScalar(rtype reflect.Type) bool {
+	if _, ok := converters.Load(rtype); ok {
+		return true
+	}
+
+	if implements(rtype, setterType) || implements(rtype, textUnmarshalerType) {
+		return true
+	}
+
+	switch rtype.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array:
+		return false
+	case reflect.Slice:
+		return rtype.Elem().Kind() == reflect.Uint8
+	default:
+		return true
+	}
+}
+
+func asSetter(target reflect.Value) (setter reflectio.Setter, ok bool) {
+	if target.CanAddr() {
+		if setter, ok = target.Addr().Interface().(reflectio.Setter); ok {
+			return
+		}
+	}
+
+	if !target.CanInterface() {
+		return
+	}
+
+	setter, ok = target.Interface().(reflectio.Setter)
+	return
+}
+
+func asTextUnmarshaler(target reflect.Value) (u encoding.TextUnmarshaler, ok bool) {
+	if !target.CanAddr() {
+		return
+	}
+
+	u, ok = target.Addr().Interface().(encoding.TextUnmarshaler)
+	return
+}
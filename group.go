@@ -9,9 +9,13 @@ type Group interface {
 	PUT(route string, hs ...Handler) error
 	DELETE(route string, hs ...Handler) error
 	OPTIONS(route string, hs ...Handler) error
+	PATCH(route string, hs ...Handler) error
 
 	Handle(method, route string, hs ...Handler) error
 	Group(route string, hs ...Handler) Group
+
+	Consumes(mediaTypes ...string) Group
+	Produces(mediaTypes ...string) Group
 }
 
 func newGroup(r *Router, route string, hs ...Handler) *group {
@@ -27,72 +31,40 @@ type group struct {
 	r     *Router
 	route string
 	hs    []Handler
+
+	// consumes and produces are the media types declared for the group's routes
+	consumes []string
+	produces []string
 }
 
 // GET will set a GET endpoint
 func (g *group) GET(route string, hs ...Handler) (err error) {
-	if g.route != "" {
-		route = path.Join(g.route, route)
-	}
-
-	if len(g.hs) > 0 {
-		ghs := append([]Handler{}, g.hs...)
-		hs = append(ghs, hs...)
-	}
-
-	return g.r.GET(route, newHandler(hs))
+	return g.Handle("GET", route, hs...)
 }
 
 // PUT will set a PUT endpoint
 func (g *group) PUT(route string, hs ...Handler) (err error) {
-	if g.route != "" {
-		route = path.Join(g.route, route)
-	}
-
-	if len(g.hs) > 0 {
-		hs = append(g.hs, hs...)
-	}
-
-	return g.r.PUT(route, newHandler(hs))
+	return g.Handle("PUT", route, hs...)
 }
 
 // POST will set a POST endpoint
 func (g *group) POST(route string, hs ...Handler) (err error) {
-	if g.route != "" {
-		route = path.Join(g.route, route)
-	}
-
-	if len(g.hs) > 0 {
-		hs = append(g.hs, hs...)
-	}
-
-	return g.r.POST(route, newHandler(hs))
+	return g.Handle("POST", route, hs...)
 }
 
 // DELETE will set a DELETE endpoint
 func (g *group) DELETE(route string, hs ...Handler) (err error) {
-	if g.route != "" {
-		route = path.Join(g.route, route)
-	}
-
-	if len(g.hs) > 0 {
-		hs = append(g.hs, hs...)
-	}
-
-	return g.r.DELETE(route, newHandler(hs))
+	return g.Handle("DELETE", route, hs...)
 }
 
 // OPTIONS will set a OPTIONS endpoint
 func (g *group) OPTIONS(route string, hs ...Handler) (err error) {
-	if g.route != "" {
-		route = path.Join(g.route, route)
-	}
-
-	if len(g.hs) > 0 {
-		hs = append(g.hs, hs...)
-	}
+	return g.Handle("OPTIONS", route, hs...)
+}
 
-	return g.r.OPTIONS(route, newHandler(hs))
+// PATCH will set a PATCH endpoint
+func (g *group) PATCH(route string, hs ...Handler) (err error) {
+	return g.Handle("PATCH", route, hs...)
 }
 
 // Handle will create a route for any method
@@ -102,10 +74,11 @@ func (g *group) Handle(method, route string, hs ...Handler) (err error) {
 	}
 
 	if len(g.hs) > 0 {
-		hs = append(g.hs, hs...)
+		ghs := append([]Handler{}, g.hs...)
+		hs = append(ghs, hs...)
 	}
 
-	return g.r.Handle(method, route, newHandler(hs))
+	return g.r.handle(method, route, newHandler(hs), g.consumes, g.produces)
 }
 
 // Group will return a new group
@@ -118,5 +91,32 @@ func (g *group) Group(route string, hs ...Handler) Group {
 		hs = append(g.hs, hs...)
 	}
 
-	return newGroup(g.r, route, hs...)
+	ng := newGroup(g.r, route, hs...)
+	ng.consumes = g.consumes
+	ng.produces = g.produces
+	return ng
+}
+
+// Consumes will return a copy of the group whose routes declare the request media types they accept
+//
+// Requests with a body of any other Content-Type are responded to with a 415
+// before the handlers are called, advertising the accepted media types within
+// the Accept-Post, Accept-Patch or Accept header (by request method). Bind
+// decodes bodies without a Content-Type as the first declared media type which
+// is not a wildcard. Media types may contain wildcards (e.g. image/*).
+func (g *group) Consumes(mediaTypes ...string) Group {
+	ng := *g
+	ng.consumes = newMediaTypes(mediaTypes)
+	return &ng
+}
+
+// Produces will return a copy of the group whose routes declare the response media types they write
+//
+// Requests whose Accept header does not accept any of the declared media types
+// are responded to with a 406 before the handlers are called, and Write only
+// negotiates between the declared media types.
+func (g *group) Produces(mediaTypes ...string) Group {
+	ng := *g
+	ng.produces = newMediaTypes(mediaTypes)
+	return &ng
 }
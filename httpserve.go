@@ -25,6 +25,12 @@ var (
 	ErrInvalidWildcardLocation = errors.New("wildcards can only directly follow a forward slash")
 	// ErrContextIsClosed is returned when write actions are attempted on a closed context
 	ErrContextIsClosed = errors.New("cannot perform write actions on a closed context")
+	// ErrInvalidBindValue is returned when a bind helper is provided a value which is not a pointer to a struct
+	ErrInvalidBindValue = errors.New("bind value must be a pointer to a struct")
+	// ErrRequestEntityTooLarge is returned when a request body exceeds the maximum body size
+	ErrRequestEntityTooLarge error = newStatusError(413, "request entity too large")
+	// ErrUnsupportedMediaType is returned when a request body has a content type without a registered codec
+	ErrUnsupportedMediaType error = newStatusError(415, "unsupported media type")
 )
 
 var defaultConfig = Config{
@@ -75,6 +81,11 @@ func (s *Serve) OPTIONS(route string, hs ...Handler) (err error) {
 	return s.g.OPTIONS(route, hs...)
 }
 
+// PATCH will set a PATCH endpoint
+func (s *Serve) PATCH(route string, hs ...Handler) (err error) {
+	return s.g.PATCH(route, hs...)
+}
+
 // Handle will create a route for any method
 func (s *Serve) Handle(method, route string, hs ...Handler) (err error) {
 	return s.g.Handle(method, route, hs...)
@@ -85,6 +96,21 @@ func (s *Serve) Group(route string, hs ...Handler) Group {
 	return s.g.Group(route, hs...)
 }
 
+// Consumes will return a group whose routes declare the request media types they accept, see Group.Consumes
+func (s *Serve) Consumes(mediaTypes ...string) Group {
+	return s.g.Consumes(mediaTypes...)
+}
+
+// Produces will return a group whose routes declare the response media types they write, see Group.Produces
+func (s *Serve) Produces(mediaTypes ...string) Group {
+	return s.g.Produces(mediaTypes...)
+}
+
+// Routes will return the registered routes, including the media types they consume and produce
+func (s *Serve) Routes() []RouteInfo {
+	return s.g.r.Routes()
+}
+
 // Listen will listen on a given port
 func (s *Serve) Listen(port uint16) (err error) {
 	return s.ListenWithConfig(port, defaultConfig)
@@ -175,6 +201,68 @@ func (s *Serve) Set404(h Handler) {
 	s.g.r.SetNotFound(h)
 }
 
+// SetCodec will set the codec for a media type, a nil codec will remove the media type
+func (s *Serve) SetCodec(mediaType string, c Codec) {
+	s.g.r.SetCodec(mediaType, c)
+}
+
+// SetMaxBodySize will set the maximum request body size, zero is unlimited
+//
+// Requests exceeding the limit will cause the Context bind helpers to return ErrRequestEntityTooLarge.
+// The limit can be overridden per route using the MaxBodySize handler.
+func (s *Serve) SetMaxBodySize(n int64) {
+	s.g.r.SetMaxBodySize(n)
+}
+
+// SetBodyMemoryLimit will set the size at which request bodies buffered by Context.Body are spilled to a temporary file
+func (s *Serve) SetBodyMemoryLimit(n int64) {
+	s.g.r.SetBodyMemoryLimit(n)
+}
+
+// SetValidateResponses will set whether or not JSON responses are validated against the schema set by ResponseSchema
+//
+// This is intended for debug and test environments, violations are reported to the SetOnError func.
+func (s *Serve) SetValidateResponses(enabled bool) {
+	s.g.r.SetValidateResponses(enabled)
+}
+
+// SetValidateOnBind will set whether or not values are validated after being bound by the Context bind helpers
+func (s *Serve) SetValidateOnBind(enabled bool) {
+	s.g.r.SetValidateOnBind(enabled)
+}
+
+// SetEnvelope will set the envelope for JSON responses written by WriteJSON and JSONResponse
+//
+// The envelope can be overridden per group or route using the JSONEnvelope handler.
+func (s *Serve) SetEnvelope(e Envelope) {
+	s.g.r.SetEnvelope(e)
+}
+
+// SetPagination will set the limit bounds and cursor secret for the Context pagination helpers
+func (s *Serve) SetPagination(cfg PaginationConfig) {
+	s.g.r.SetPagination(cfg)
+}
+
+// SetJSONPCallbackParam will set the query parameter Context.WriteJSONP reads the callback from, defaults to "callback"
+func (s *Serve) SetJSONPCallbackParam(param string) {
+	s.g.r.SetJSONPCallbackParam(param)
+}
+
+// SetMultipart will set the file size limits, memory limit and allowed file types for binding multipart/form-data bodies
+func (s *Serve) SetMultipart(cfg MultipartConfig) {
+	s.g.r.SetMultipart(cfg)
+}
+
+// Set405 will set the method not allowed handler
+func (s *Serve) Set405(h Handler) {
+	s.g.r.SetMethodNotAllowed(h)
+}
+
+// SetProblemDetails will set whether or not the built-in 404, 405 and panic responses are written as problem details
+func (s *Serve) SetProblemDetails(enabled bool) {
+	s.g.r.SetProblemDetails(enabled)
+}
+
 // SetPanic will set the panic handler
 func (s *Serve) SetPanic(h PanicHandler) {
 	s.g.r.SetPanic(h)
@@ -0,0 +1,163 @@
+package httpserve
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// JSONEngineDefault will use sonic on supported platforms and encoding/json otherwise
+	JSONEngineDefault JSONEngine = iota
+	// JSONEngineSonic will use github.com/bytedance/sonic
+	JSONEngineSonic
+	// JSONEngineStandard will use encoding/json
+	JSONEngineStandard
+)
+
+// jsonAPI is the JSON engine used throughout the package
+var jsonAPI = newJSONEngine(JSONConfig{})
+
+// JSONEngine represents a JSON implementation
+type JSONEngine uint8
+
+// JSONConfig is the configuration of the package JSON engine
+type JSONConfig struct {
+	// Engine is the JSON implementation to use
+	Engine JSONEngine
+
+	// DisallowUnknownFields will cause decoding to fail when an object contains keys which do not match any struct field
+	DisallowUnknownFields bool
+	// UseNumber will decode numbers into an interface{} as json.Number instead of float64
+	UseNumber bool
+
+	// EscapeHTML will escape HTML characters within JSON strings
+	EscapeHTML bool
+	// SortMapKeys will sort map keys when encoding, encoding/json always sorts map keys
+	SortMapKeys bool
+	// Prefix and Indent will indent encoded values when Indent is set
+	Prefix string
+	Indent string
+}
+
+// SetJSONConfig will set the JSON engine and options used by the package
+//
+// The MessagePack and CBOR codecs share the DisallowUnknownFields and
+// SortMapKeys options.
+//
+// Note: This is not safe to call while requests are being served, it is
+// intended to be called during initialization.
+func SetJSONConfig(cfg JSONConfig) {
+	jsonAPI = newJSONEngine(cfg)
+	msgpackAPI = newMsgpackEngine(cfg)
+	cborAPI = newCBOREngine(cfg)
+}
+
+func newJSONEngine(cfg JSONConfig) jsonEngine {
+	if cfg.Engine == JSONEngineDefault {
+		cfg.Engine = defaultJSONEngine
+	}
+
+	if cfg.Engine == JSONEngineStandard {
+		return &stdJSON{cfg: cfg}
+	}
+
+	var s sonicJSON
+	s.cfg = cfg
+	s.api = sonic.Config{
+		EscapeHTML:            cfg.EscapeHTML,
+		SortMapKeys:           cfg.SortMapKeys,
+		UseNumber:             cfg.UseNumber,
+		DisallowUnknownFields: cfg.DisallowUnknownFields,
+	}.Froze()
+	return &s
+}
+
+type jsonEngine interface {
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(bs []byte, value interface{}) error
+	// Encode will write the encoded value to the writer, followed by a newline
+	Encode(w io.Writer, value interface{}) error
+	// Decode will decode the next value from the reader
+	Decode(r io.Reader, value interface{}) error
+}
+
+type sonicJSON struct {
+	cfg JSONConfig
+	api sonic.API
+}
+
+func (s *sonicJSON) Marshal(value interface{}) ([]byte, error) {
+	return s.api.Marshal(value)
+}
+
+func (s *sonicJSON) Unmarshal(bs []byte, value interface{}) error {
+	return s.api.Unmarshal(bs, value)
+}
+
+func (s *sonicJSON) Encode(w io.Writer, value interface{}) error {
+	enc := s.api.NewEncoder(w)
+	if len(s.cfg.Indent) > 0 {
+		enc.SetIndent(s.cfg.Prefix, s.cfg.Indent)
+	}
+
+	return enc.Encode(value)
+}
+
+func (s *sonicJSON) Decode(r io.Reader, value interface{}) error {
+	return s.api.NewDecoder(r).Decode(value)
+}
+
+type stdJSON struct {
+	cfg JSONConfig
+}
+
+func (s *stdJSON) Marshal(value interface{}) (bs []byte, err error) {
+	if s.cfg.EscapeHTML {
+		return json.Marshal(value)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err = enc.Encode(value); err != nil {
+		return
+	}
+
+	// Remove the trailing newline
+	bs = bytes.TrimSuffix(buf.Bytes(), []byte{'\n'})
+	return
+}
+
+func (s *stdJSON) Unmarshal(bs []byte, value interface{}) error {
+	if !s.cfg.UseNumber && !s.cfg.DisallowUnknownFields {
+		return json.Unmarshal(bs, value)
+	}
+
+	return s.Decode(bytes.NewReader(bs), value)
+}
+
+func (s *stdJSON) Encode(w io.Writer, value interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(s.cfg.EscapeHTML)
+	if len(s.cfg.Indent) > 0 {
+		enc.SetIndent(s.cfg.Prefix, s.cfg.Indent)
+	}
+
+	return enc.Encode(value)
+}
+
+func (s *stdJSON) Decode(r io.Reader, value interface{}) error {
+	dec := json.NewDecoder(r)
+	if s.cfg.UseNumber {
+		dec.UseNumber()
+	}
+
+	if s.cfg.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	return dec.Decode(value)
+}
@@ -0,0 +1,93 @@
+package httpserve
+
+import "errors"
+
+// ErrorCoder is an optional interface for errors which provide a machine-readable code
+type ErrorCoder interface {
+	ErrorCode() string
+}
+
+// ErrorFielder is an optional interface for errors which are associated with an input field
+type ErrorFielder interface {
+	ErrorField() string
+}
+
+// ErrorDetailer is an optional interface for errors which provide additional details
+type ErrorDetailer interface {
+	ErrorDetails() interface{}
+}
+
+// NewError will return a new Error for a provided error
+//
+// The optional ErrorCoder, ErrorFielder and ErrorDetailer interfaces are
+// checked throughout the error chain to populate the associated fields.
+func NewError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	e = &Error{Message: err.Error()}
+
+	var coder ErrorCoder
+	if errors.As(err, &coder) {
+		e.Code = coder.ErrorCode()
+	}
+
+	var fielder ErrorFielder
+	if errors.As(err, &fielder) {
+		e.Field = fielder.ErrorField()
+	}
+
+	var detailer ErrorDetailer
+	if errors.As(err, &detailer) {
+		e.Details = detailer.ErrorDetails()
+	}
+
+	return e
+}
+
+// Error is the structured JSON representation of an error
+type Error struct {
+	Message string      `json:"message"`
+	Code    string      `json:"code,omitempty"`
+	Field   string      `json:"field,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Error will return the error message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorCode will return the error code
+func (e *Error) ErrorCode() string {
+	return e.Code
+}
+
+// ErrorField will return the error field
+func (e *Error) ErrorField() string {
+	return e.Field
+}
+
+// ErrorDetails will return the error details
+func (e *Error) ErrorDetails() interface{} {
+	return e.Details
+}
+
+func newErrors(errs []error) (out []*Error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	out = make([]*Error, 0, len(errs))
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		out = append(out, NewError(err))
+	}
+
+	return
+}
@@ -2,15 +2,79 @@ package httpserve
 
 import (
 	"bytes"
-	"encoding/json"
 	"io"
+	"regexp"
+	"strings"
 )
 
-var jsonPEnding = []byte(");\n")
+const (
+	defaultJSONPCallbackParam = "callback"
+	// maxJSONPCallbackLength is the maximum length of a JSONP callback name
+	maxJSONPCallbackLength = 128
+)
+
+var (
+	// ErrInvalidCallback is returned when a JSONP callback is not a safe JavaScript identifier
+	ErrInvalidCallback error = newStatusError(400, "invalid JSONP callback")
+
+	// jsonPPrefix prevents the response from being interpreted as anything other than a script (e.g. Rosetta Flash)
+	jsonPPrefix = []byte("/**/")
+	jsonPEnding = []byte(");\n")
+
+	jsonPCallbackRegexp = regexp.MustCompile(`^[a-zA-Z_$][0-9a-zA-Z_$]*(\.[a-zA-Z_$][0-9a-zA-Z_$]*)*$`)
+
+	jsonPReservedWords = map[string]struct{}{}
+)
+
+func init() {
+	for _, word := range strings.Fields(`
+		await break case catch class const continue debugger default delete do else enum export extends
+		false finally for function if implements import in instanceof interface let new null package
+		private protected public return static super switch this throw true try typeof var void while with yield`) {
+		jsonPReservedWords[word] = struct{}{}
+	}
+}
+
+// ValidJSONPCallback will return whether or not a callback is a safe JavaScript identifier
+//
+// Callbacks may be dot-separated identifiers (e.g. "app.handlers.onData"),
+// each consisting of ASCII letters, digits, "_" and "$" and not being a
+// reserved word.
+func ValidJSONPCallback(callback string) bool {
+	if len(callback) > maxJSONPCallbackLength || !jsonPCallbackRegexp.MatchString(callback) {
+		return false
+	}
+
+	for _, part := range strings.Split(callback, ".") {
+		if _, ok := jsonPReservedWords[part]; ok {
+			return false
+		}
+	}
+
+	return true
+}
 
 // NewJSONPResponse will return a new text response
+//
+// Error values set the status code passed through within the body to the
+// status code associated with the error (see ErrorStatusCode), falling back to
+// a 400. The response itself is always sent with a 200, see StatusCode.
 func NewJSONPResponse(callback string, value interface{}) *JSONPResponse {
+	code := 200
+	switch v := value.(type) {
+	case error:
+		code = ErrorStatusCode(v, 400)
+	case []error:
+		code = 400
+	}
+
+	return NewJSONPStatusResponse(code, callback, value)
+}
+
+// NewJSONPStatusResponse will return a new JSONP response, passing the provided status code through within the body as meta.status
+func NewJSONPStatusResponse(code int, callback string, value interface{}) *JSONPResponse {
 	var j JSONPResponse
+	j.code = code
 	j.callback = callback
 	j.val = value
 	return &j
@@ -18,8 +82,11 @@ func NewJSONPResponse(callback string, value interface{}) *JSONPResponse {
 
 // JSONPResponse is a basic text response
 type JSONPResponse struct {
+	code     int
 	callback string
 	val      interface{}
+	// envelope is the envelope the value is wrapped with, DefaultEnvelope is used when unset
+	envelope Envelope
 }
 
 // ContentType returns the content type
@@ -28,39 +95,44 @@ func (j *JSONPResponse) ContentType() (contentType string) {
 }
 
 // StatusCode returns the status code
+//
+// JSONP responses are always sent with a 200, as browsers do not execute
+// scripts responded to with an error status. The status code is passed
+// through within the body as meta.status instead.
 func (j *JSONPResponse) StatusCode() (code int) {
 	return 200
 }
 
-func (j *JSONPResponse) newValue() (value JSONValue) {
-	// Switch on associated value's type
-	switch v := j.val.(type) {
-	case error:
-		// Type is a single error value, create new error slice with error as only item
-		value.PushErrors(v)
-	case []error:
-		// Type is an error slice, set errors as the value
-		value.PushErrors(v...)
-	default:
-		value.Data = j.val
+func (j *JSONPResponse) newValue() (value interface{}, err error) {
+	e := j.envelope
+	if e == nil {
+		e = DefaultEnvelope
 	}
 
-	return
+	return e.Wrap(j.code, j.val, Meta{"status": j.code})
 }
 
 // WriteTo will write to a given io.Writer
+//
+// ErrInvalidCallback is returned when the callback is not a safe JavaScript identifier.
 func (j *JSONPResponse) WriteTo(w io.Writer) (n int64, err error) {
+	if !ValidJSONPCallback(j.callback) {
+		return 0, ErrInvalidCallback
+	}
+
+	// Initialize a new JSON value
+	var value interface{}
+	if value, err = j.newValue(); err != nil {
+		return
+	}
+
 	// Initialize buffer
 	buf := bytes.NewBuffer(nil)
 	// Write callback func
+	buf.Write(jsonPPrefix)
 	buf.WriteString(j.callback + "(")
-	// Initialize a new JSON value
-	value := j.newValue()
-	// Initialize a new JSON encoder
-	enc := json.NewEncoder(buf)
-
 	// Encode the responder
-	if err = enc.Encode(value); err != nil {
+	if err = jsonAPI.Encode(buf, value); err != nil {
 		return
 	}
 
@@ -70,6 +142,36 @@ func (j *JSONPResponse) WriteTo(w io.Writer) (n int64, err error) {
 	buf.Write(jsonPEnding)
 
 	// Flush buffer to writer
-	_, err = w.Write(buf.Bytes())
-	return
+	return buf.WriteTo(w)
+}
+
+// WriteJSONP will write a JSONP response using the callback provided within the request query
+//
+// The callback is read from the query parameter set by Serve.SetJSONPCallbackParam,
+// defaulting to "callback". When no callback is provided, the value is written
+// as JSON using WriteJSON. Invalid callbacks are responded to with a 400.
+//
+// JSONP responses are sent with a 200 so browsers execute the callback, the
+// status code is passed through within the body as meta.status.
+func (c *Context) WriteJSONP(statusCode int, value interface{}) {
+	callback := c.request.URL.Query().Get(c.jsonPCallbackParam())
+	if len(callback) == 0 {
+		c.WriteJSON(statusCode, value)
+		return
+	}
+
+	if !ValidJSONPCallback(callback) {
+		c.WriteJSON(400, ErrInvalidCallback)
+		return
+	}
+
+	c.Respond(NewJSONPStatusResponse(statusCode, callback, value))
+}
+
+func (c *Context) jsonPCallbackParam() string {
+	if c.router == nil || len(c.router.jsonPCallbackParam) == 0 {
+		return defaultJSONPCallbackParam
+	}
+
+	return c.router.jsonPCallbackParam
 }
@@ -0,0 +1,143 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidJSONPCallback(t *testing.T) {
+	tcs := []struct {
+		callback string
+		valid    bool
+	}{
+		{callback: "callback", valid: true},
+		{callback: "$jQuery_123", valid: true},
+		{callback: "app.handlers.onData", valid: true},
+		{callback: "", valid: false},
+		{callback: "alert(1)//", valid: false},
+		{callback: "1foo", valid: false},
+		{callback: "foo..bar", valid: false},
+		{callback: "foo.", valid: false},
+		{callback: "foo[0]", valid: false},
+		{callback: "function", valid: false},
+		{callback: "foo.delete", valid: false},
+		{callback: strings.Repeat("a", 129), valid: false},
+	}
+
+	for _, tc := range tcs {
+		if valid := ValidJSONPCallback(tc.callback); valid != tc.valid {
+			t.Fatalf("invalid result for %q, expected %v and received %v", tc.callback, tc.valid, valid)
+		}
+	}
+}
+
+func TestContext_WriteJSONP(t *testing.T) {
+	tcs := []struct {
+		name     string
+		query    string
+		code     int
+		value    interface{}
+		envelope Envelope
+		expected int
+		body     string
+		nosniff  bool
+	}{
+		{
+			name:     "callback",
+			query:    "?callback=cb",
+			code:     200,
+			value:    map[string]string{"foo": "bar"},
+			expected: 200,
+			body:     `/**/cb({"data":{"foo":"bar"},"meta":{"status":200}});` + "\n",
+			nosniff:  true,
+		},
+		{
+			name:     "error status passthrough",
+			query:    "?callback=cb",
+			code:     404,
+			value:    errors.New("not found"),
+			expected: 200,
+			body:     `/**/cb({"errors":[{"message":"not found"}],"meta":{"status":404}});` + "\n",
+			nosniff:  true,
+		},
+		{
+			name:     "bare envelope error status passthrough",
+			query:    "?callback=cb",
+			code:     404,
+			value:    errors.New("not found"),
+			envelope: BareEnvelope,
+			expected: 200,
+			body:     `/**/cb({"errors":[{"message":"not found"}],"meta":{"status":404}});` + "\n",
+			nosniff:  true,
+		},
+		{
+			name:     "no callback",
+			query:    "",
+			code:     200,
+			value:    map[string]string{"foo": "bar"},
+			expected: 200,
+			body:     `{"data":{"foo":"bar"}}` + "\n",
+		},
+		{
+			name:     "invalid callback",
+			query:    "?callback=alert(1)//",
+			code:     200,
+			value:    map[string]string{"foo": "bar"},
+			expected: 400,
+			body:     `{"errors":[{"message":"invalid JSONP callback"}]}` + "\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx := newContext(w, httptest.NewRequest("GET", "/"+tc.query, nil), nil)
+			ctx.envelope = tc.envelope
+			ctx.WriteJSONP(tc.code, tc.value)
+			if w.Code != tc.expected {
+				t.Fatalf("invalid status code, expected %d and received %d", tc.expected, w.Code)
+			}
+
+			if w.Body.String() != tc.body {
+				t.Fatalf("invalid body, expected %q and received %q", tc.body, w.Body.String())
+			}
+
+			if nosniff := w.Header().Get("X-Content-Type-Options") == "nosniff"; nosniff != tc.nosniff {
+				t.Fatalf("invalid nosniff header, expected %v and received %v", tc.nosniff, nosniff)
+			}
+		})
+	}
+}
+
+func TestJSONPResponse(t *testing.T) {
+	resp := NewJSONPResponse("cb", ErrRequestEntityTooLarge)
+	if code := resp.StatusCode(); code != 200 {
+		t.Fatalf("invalid status code, expected %d and received %d", 200, code)
+	}
+
+	w := httptest.NewRecorder()
+	if _, err := resp.WriteTo(w); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `/**/cb({"errors":[{"message":"request entity too large"}],"meta":{"status":413}});` + "\n"; w.Body.String() != expected {
+		t.Fatalf("invalid body, expected %q and received %q", expected, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if _, err := NewJSONPResponse("alert(1)//", "foo").WriteTo(w); !errors.Is(err, ErrInvalidCallback) {
+		t.Fatalf("expected ErrInvalidCallback, received %v", err)
+	}
+
+	r := newRouter()
+	r.SetJSONPCallbackParam("jsonp")
+	w = httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/?jsonp=cb", nil), nil)
+	ctx.router = r
+	ctx.WriteJSONP(200, "foo")
+	if expected := `/**/cb({"data":"foo","meta":{"status":200}});` + "\n"; w.Body.String() != expected {
+		t.Fatalf("invalid body, expected %q and received %q", expected, w.Body.String())
+	}
+}
@@ -1,8 +1,6 @@
 package httpserve
 
 import (
-	"encoding/json"
-	"fmt"
 	"io"
 )
 
@@ -18,6 +16,11 @@ func NewJSONResponse(code int, value interface{}) *JSONResponse {
 type JSONResponse struct {
 	code int
 	val  interface{}
+	// fields are the field paths the data is projected to, see SetFields
+	fields []string
+	meta   Meta
+	// envelope is the envelope the value is wrapped with, DefaultEnvelope is used when unset
+	envelope Envelope
 }
 
 // ContentType returns the content type
@@ -30,41 +33,37 @@ func (j *JSONResponse) StatusCode() (code int) {
 	return j.code
 }
 
-func (j *JSONResponse) newValue() (value JSONValue, err error) {
-	if j.code < 400 {
-		// Status code is not an error code, set the data as the associated value and return.
-		value.Data = j.val
-		return
+// SetMeta will set the meta included alongside the response data
+func (j *JSONResponse) SetMeta(meta Meta) {
+	j.meta = meta
+}
+
+func (j *JSONResponse) newValue() (value interface{}, err error) {
+	val := j.val
+	if len(j.fields) > 0 && j.code < 400 {
+		if val, err = projectValue(val, j.fields); err != nil {
+			return
+		}
 	}
 
-	// Switch on associated value's type
-	switch v := j.val.(type) {
-	case error:
-		// Type is a single error value, create new error slice with error as only item
-		value.PushErrors(v)
-	case []error:
-		// Type is an error slice, set errors as the value
-		value.PushErrors(v...)
-	default:
-		// Invalid error value, return error
-		err = fmt.Errorf("invalid type for an error response: %#v", v)
+	e := j.envelope
+	if e == nil {
+		e = DefaultEnvelope
 	}
 
-	return
+	return e.Wrap(j.code, val, j.meta)
 }
 
 // WriteTo will write to a given io.Writer
 func (j *JSONResponse) WriteTo(w io.Writer) (n int64, err error) {
-	var value JSONValue
+	var value interface{}
 	// Initialize a new JSON value
 	if value, err = j.newValue(); err != nil {
 		// Error encountered while initializing responder, return early
 		return
 	}
 
-	// Initialize a new JSON encoder
-	enc := json.NewEncoder(w)
 	// Encode the responder
-	err = enc.Encode(value)
+	err = jsonAPI.Encode(w, value)
 	return
 }
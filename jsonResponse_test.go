@@ -3,6 +3,7 @@ package httpserve
 import (
 	"bytes"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -48,3 +49,43 @@ type TestJSONStruct struct {
 	Name string `json:"name"`
 	Age  int    `json:"age"`
 }
+
+func TestJSONResponseError_structured(t *testing.T) {
+	resp := NewJSONResponse(400, []error{
+		errors.New("plain error"),
+		&Error{Message: "invalid email", Code: "invalid_email", Field: "email"},
+	})
+	buf := bytes.NewBuffer(nil)
+
+	if _, err := resp.WriteTo(buf); err != nil {
+		t.Fatalf("error writing: %v", err)
+	}
+
+	expected := `{"errors":[{"message":"plain error"},{"message":"invalid email","code":"invalid_email","field":"email"}]}`
+	if str := strings.TrimSpace(buf.String()); str != expected {
+		t.Fatalf("invalid value, expected %s and received %s", expected, str)
+	}
+
+	var nts TestJSONStruct
+	err := UnmarshalJSONValue(buf.Bytes(), &nts)
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected *Error and received %#v", err)
+	}
+
+	if e.Message != "plain error" {
+		t.Fatalf("invalid message, expected \"%s\" and received \"%s\"", "plain error", e.Message)
+	}
+}
+
+func TestUnmarshalJSONValue_nullErrors(t *testing.T) {
+	var nts TestJSONStruct
+	if err := UnmarshalJSONValue([]byte(`{"data":{"name":"John Doe"},"errors":[null]}`), &nts); err != nil {
+		t.Fatalf("expected nil error, received %v", err)
+	}
+
+	if nts.Name != "John Doe" {
+		t.Fatalf("invalid name, expected \"%s\" and received \"%s\"", "John Doe", nts.Name)
+	}
+}
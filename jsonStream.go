@@ -0,0 +1,126 @@
+package httpserve
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+)
+
+// BindJSONStream will return an iterator of the elements of a JSON array or newline delimited JSON request body
+//
+// Elements are decoded one at a time, so the body is never held in memory as
+// a whole. Element decoding (and validation) errors are yielded as
+// *ElementError values and iteration continues with the next element. Syntax
+// and read errors are yielded once and end the iteration. Breaking out of the
+// loop stops reading the body, allowing the handler to respond early.
+func BindJSONStream[T any](ctx *Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		defer ctx.request.Body.Close()
+		body, err := ctx.bodyReader()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		var dec *json.Decoder
+		if dec, err = newJSONStreamDecoder(ctx, body); err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for i := 0; dec.More(); i++ {
+			var raw json.RawMessage
+			if err = dec.Decode(&raw); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var value T
+			if err = jsonAPI.Unmarshal(raw, &value); err == nil {
+				err = ctx.autoValidate(&value)
+			}
+
+			if err != nil {
+				err = &ElementError{Index: i, Err: err}
+			}
+
+			if !yield(value, err) {
+				return
+			}
+		}
+	}
+}
+
+// newJSONStreamDecoder will return a decoder positioned at the first element of the stream
+func newJSONStreamDecoder(ctx *Context, body io.Reader) (dec *json.Decoder, err error) {
+	br := bufio.NewReader(body)
+	dec = json.NewDecoder(br)
+	if isNDJSON(ctx.request.Header.Get("Content-Type")) {
+		return
+	}
+
+	var first byte
+	if first, err = peekNonSpace(br); err == io.EOF {
+		// Empty body, return decoder with no elements
+		return dec, nil
+	} else if err != nil {
+		return
+	}
+
+	if first != '[' {
+		// Not an array, treat the body as a stream of values
+		return
+	}
+
+	// Consume the opening delimiter of the array
+	_, err = dec.Token()
+	return
+}
+
+func isNDJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-ndjson", "application/jsonl":
+		return true
+	default:
+		return false
+	}
+}
+
+func peekNonSpace(br *bufio.Reader) (b byte, err error) {
+	for {
+		var bs []byte
+		if bs, err = br.Peek(1); err != nil {
+			return
+		}
+
+		switch b = bs[0]; b {
+		case ' ', '\t', '\r', '\n':
+			if _, err = br.ReadByte(); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// ElementError is an error associated with an element of a streamed request body
+type ElementError struct {
+	Index int
+	Err   error
+}
+
+// Error will return the error message
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+// Unwrap will return the underlying error
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,68 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBindJSONStream(t *testing.T) {
+	tcs := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "array", contentType: "application/json", body: ` [{"name":"a","age":1}, {"name":"b","age":"two"}, {"name":"c","age":3}]`},
+		{name: "ndjson", contentType: "application/x-ndjson", body: "{\"name\":\"a\",\"age\":1}\n{\"name\":\"b\",\"age\":\"two\"}\n{\"name\":\"c\",\"age\":3}\n"},
+	}
+
+	for _, tc := range tcs {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		ctx := newContext(httptest.NewRecorder(), req, nil)
+
+		var (
+			names []string
+			errs  []error
+		)
+
+		for v, err := range BindJSONStream[TestJSONStruct](ctx) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			names = append(names, v.Name)
+		}
+
+		if strings.Join(names, ",") != "a,c" {
+			t.Fatalf("%s: invalid names, expected %v and received %v", tc.name, "a,c", names)
+		}
+
+		var ee *ElementError
+		if len(errs) != 1 || !errors.As(errs[0], &ee) || ee.Index != 1 {
+			t.Fatalf("%s: invalid errors, received %v", tc.name, errs)
+		}
+	}
+}
+
+func TestBindJSONStream_break(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`[{"name":"a"},{"name":"b"},{"name":"c"]`))
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	var n int
+	for _, err := range BindJSONStream[TestJSONStruct](ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n++; n == 2 {
+			break
+		}
+	}
+
+	if n != 2 {
+		t.Fatalf("invalid number of elements, expected %d and received %d", 2, n)
+	}
+}
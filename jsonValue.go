@@ -1,19 +1,17 @@
 package httpserve
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-
-	"github.com/bytedance/sonic"
 )
 
 // DecodeJSONValue will decode a JSON value
 func DecodeJSONValue(r io.Reader, val interface{}) (err error) {
 	var jv JSONValue
 	jv.Data = val
-	dec := sonic.ConfigDefault.NewDecoder(r)
-	if err = dec.Decode(&jv); err != nil {
+	if err = jsonAPI.Decode(r, &jv); err != nil {
 		return
 	}
 
@@ -24,7 +22,7 @@ func DecodeJSONValue(r io.Reader, val interface{}) (err error) {
 func UnmarshalJSONValue(bs []byte, val interface{}) (err error) {
 	var jv JSONValue
 	jv.Data = val
-	if err = sonic.Unmarshal(bs, &jv); err != nil {
+	if err = jsonAPI.Unmarshal(bs, &jv); err != nil {
 		return
 	}
 
@@ -40,6 +38,9 @@ func makeJSONValue(statusCode int, data interface{}) (val JSONValue, err error)
 
 	// Switch on associated value's type
 	switch v := data.(type) {
+	case FieldErrors:
+		// Type is a list of field errors, set each field error as a value
+		val.PushErrors(v.errors()...)
 	case error:
 		// Type is a single error value, create new error slice with error as only item
 		val.PushErrors(v)
@@ -59,8 +60,79 @@ func makeJSONValue(statusCode int, data interface{}) (val JSONValue, err error)
 type JSONValue struct {
 	Data   interface{} `json:"data,omitempty"`
 	Errors []error     `json:"errors,omitempty"`
+	Meta   Meta        `json:"meta,omitempty"`
 }
 
+// PushErrors will append errors to the JSON value
 func (j *JSONValue) PushErrors(errs ...error) {
 	j.Errors = append(j.Errors, errs...)
 }
+
+// MarshalJSON will marshal the JSON value, errors are marshaled as structured Error objects
+func (j JSONValue) MarshalJSON() (bs []byte, err error) {
+	return jsonAPI.Marshal(j.encoded())
+}
+
+// UnmarshalJSON will unmarshal the JSON value, errors are unmarshaled as *Error values
+//
+// When Data is set to a pointer prior to unmarshaling, the data will be unmarshaled into it.
+func (j *JSONValue) UnmarshalJSON(bs []byte) (err error) {
+	var in decodedJSONValue
+	if err = jsonAPI.Unmarshal(bs, &in); err != nil {
+		return
+	}
+
+	if len(in.Data) > 0 && string(in.Data) != "null" {
+		if err = j.decodeData(jsonAPI.Unmarshal, in.Data); err != nil {
+			return
+		}
+	}
+
+	j.setDecoded(in.Errors, in.Meta)
+	return
+}
+
+// encoded will return the value encoded in place of the JSON value
+func (j *JSONValue) encoded() (out encodedJSONValue) {
+	out.Data = j.Data
+	out.Errors = newErrors(j.Errors)
+	out.Meta = j.Meta
+	return
+}
+
+// decodeData will unmarshal the data of the JSON value, into Data when set to a pointer
+func (j *JSONValue) decodeData(unmarshal func(bs []byte, value interface{}) error, bs []byte) (err error) {
+	if j.Data != nil {
+		return unmarshal(bs, j.Data)
+	}
+
+	var data interface{}
+	err = unmarshal(bs, &data)
+	j.Data = data
+	return
+}
+
+func (j *JSONValue) setDecoded(errs []*Error, meta Meta) {
+	j.Meta = meta
+	j.Errors = j.Errors[:0]
+	for _, e := range errs {
+		if e == nil {
+			// Null error entries carry no error, skip them rather than storing a typed nil
+			continue
+		}
+
+		j.Errors = append(j.Errors, e)
+	}
+}
+
+type encodedJSONValue struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+	Meta   Meta        `json:"meta,omitempty"`
+}
+
+type decodedJSONValue struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []*Error        `json:"errors"`
+	Meta   Meta            `json:"meta"`
+}
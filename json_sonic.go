@@ -0,0 +1,6 @@
+//go:build (amd64 && go1.17 && !go1.27) || (arm64 && go1.20 && !go1.27)
+
+package httpserve
+
+// defaultJSONEngine is sonic on the platforms natively supported by sonic
+const defaultJSONEngine = JSONEngineSonic
@@ -0,0 +1,34 @@
+package httpserve
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetJSONConfig(t *testing.T) {
+	defer SetJSONConfig(JSONConfig{})
+
+	type testStruct struct {
+		Name string `json:"name"`
+	}
+
+	for _, engine := range []JSONEngine{JSONEngineSonic, JSONEngineStandard} {
+		SetJSONConfig(JSONConfig{Engine: engine, EscapeHTML: true, SortMapKeys: true, DisallowUnknownFields: true})
+
+		buf := bytes.NewBuffer(nil)
+		resp := NewJSONResponse(200, map[string]string{"b": "<b>", "a": "&"})
+		if _, err := resp.WriteTo(buf); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "{\"data\":{\"a\":\"\\u0026\",\"b\":\"\\u003cb\\u003e\"}}\n"
+		if str := buf.String(); str != expected {
+			t.Fatalf("invalid value for engine %d, expected %q and received %q", engine, expected, str)
+		}
+
+		var ts testStruct
+		if err := UnmarshalJSONValue([]byte(`{"data":{"name":"John Doe","age":33}}`), &ts); err == nil {
+			t.Fatalf("expected error for unknown field with engine %d and received nil", engine)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package httpserve
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	Method string
+	Path   string
+	// Consumes are the request media types declared for the route, empty when undeclared
+	Consumes []string
+	// Produces are the response media types declared for the route, empty when undeclared
+	Produces []string
+}
+
+// newMediaTypes will return the normalized list of the provided media types
+func newMediaTypes(mediaTypes []string) (out []string) {
+	out = make([]string, 0, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		if mt = strings.ToLower(strings.TrimSpace(mt)); len(mt) > 0 {
+			out = append(out, mt)
+		}
+	}
+
+	return
+}
+
+// mediaTypeMatches will return whether or not a media type matches a declared media type, which may contain wildcards
+func mediaTypeMatches(declared, mediaType string) bool {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	dtyp, dsubtype, _ := strings.Cut(declared, "/")
+	if dtyp != "*" && dtyp != typ {
+		return false
+	}
+
+	return dsubtype == "*" || dsubtype == subtype
+}
+
+// checkMediaTypes will respond with a 415 or 406 when the request does not satisfy the media types declared for the route
+func (c *Context) checkMediaTypes() (ok bool) {
+	if len(c.route.consumes) > 0 && c.hasBody() {
+		if _, ok = c.consumedType(); !ok {
+			c.writeUnsupportedMediaType()
+			return
+		}
+	}
+
+	if len(c.route.produces) > 0 {
+		if _, ok = negotiate(c.request.Header.Get("Accept"), c.route.produces); !ok {
+			c.writeNotAcceptable()
+			c.close()
+			return
+		}
+	}
+
+	return true
+}
+
+// consumedType will return the media type of the request body, when the route declares the media types it consumes
+//
+// A request without a Content-Type is treated as the first declared media
+// type which is not a wildcard.
+func (c *Context) consumedType() (mediaType string, ok bool) {
+	contentType := c.request.Header.Get("Content-Type")
+	if c.route == nil || len(c.route.consumes) == 0 {
+		return contentType, true
+	}
+
+	if len(contentType) == 0 {
+		for _, declared := range c.route.consumes {
+			if !strings.Contains(declared, "*") {
+				return declared, true
+			}
+		}
+
+		return contentType, true
+	}
+
+	var err error
+	if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+		return
+	}
+
+	for _, declared := range c.route.consumes {
+		if mediaTypeMatches(declared, mediaType) {
+			return contentType, true
+		}
+	}
+
+	return
+}
+
+// negotiateCodec will return the codec for the response value, limited to the media types the route produces
+//
+// When none of the declared media types have a registered codec (e.g. a route
+// which only produces CSV), all registered codecs are negotiated. Codecs which
+// are unable to encode the value are not negotiated.
+func (c *Context) negotiateCodec(value interface{}) (mediaType string, codec Codec, ok bool) {
+	codecs := c.codecs()
+	mediaTypes := codecs.mediaTypes
+	if c.route != nil && len(c.route.produces) > 0 {
+		produced := make([]string, 0, len(c.route.produces))
+		for _, mt := range c.route.produces {
+			if _, ok = codecs.Get(mt); ok {
+				produced = append(produced, mt)
+			}
+		}
+
+		if len(produced) > 0 {
+			mediaTypes = produced
+		}
+	}
+
+	if mediaType, ok = negotiate(c.request.Header.Get("Accept"), codecs.encodable(mediaTypes, value)); !ok {
+		return
+	}
+
+	codec, _ = codecs.Get(mediaType)
+	return
+}
+
+func (c *Context) writeUnsupportedMediaType() {
+	defer c.close()
+	c.writer.Header().Set(acceptRequestHeader(c.request.Method), strings.Join(c.route.consumes, ", "))
+	if c.prefersProblem() {
+		c.writeProblem(http.StatusUnsupportedMediaType, NewProblem(http.StatusUnsupportedMediaType, nil))
+		return
+	}
+
+	c.setContentType("text/plain")
+	c.setStatusCode(http.StatusUnsupportedMediaType)
+	if _, err := c.writer.Write([]byte("415, unsupported media type")); err != nil {
+		c.errorFn(err)
+	}
+}
+
+// acceptRequestHeader will return the response header advertising the request media types accepted for a method
+func acceptRequestHeader(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "Accept-Post"
+	case http.MethodPatch:
+		return "Accept-Patch"
+	default:
+		return "Accept"
+	}
+}
@@ -0,0 +1,176 @@
+package httpserve
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroup_Consumes(t *testing.T) {
+	type testUser struct {
+		Name string `json:"name" form:"name"`
+	}
+
+	r := newRouter()
+	g := newGroup(r, "/api").Consumes(jsonContentType, formContentType)
+	g.POST("/users", func(ctx *Context) {
+		var u testUser
+		if err := ctx.Bind(&u); err != nil {
+			ctx.WriteJSON(ErrorStatusCode(err, 400), err)
+			return
+		}
+
+		ctx.WriteString(200, "text/plain", u.Name)
+	})
+
+	tcs := []struct {
+		contentType string
+		body        string
+		statusCode  int
+		expected    string
+	}{
+		{contentType: jsonContentType, body: `{"name":"John Doe"}`, statusCode: 200, expected: "John Doe"},
+		{contentType: formContentType, body: "name=John+Doe", statusCode: 200, expected: "John Doe"},
+		{contentType: "", body: `{"name":"John Doe"}`, statusCode: 200, expected: "John Doe"},
+		{contentType: xmlContentType, body: "<testUser><Name>John Doe</Name></testUser>", statusCode: 415},
+	}
+
+	for _, tc := range tcs {
+		req := httptest.NewRequest("POST", "/api/users", strings.NewReader(tc.body))
+		if len(tc.contentType) > 0 {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.statusCode {
+			t.Fatalf("invalid status code for %q, expected %d and received %d", tc.contentType, tc.statusCode, w.Code)
+		}
+
+		if tc.statusCode == 415 {
+			if accept := w.Header().Get("Accept-Post"); accept != jsonContentType+", "+formContentType {
+				t.Fatalf("invalid Accept-Post header, received %q", accept)
+			}
+
+			continue
+		}
+
+		if w.Body.String() != tc.expected {
+			t.Fatalf("invalid body, expected %q and received %q", tc.expected, w.Body.String())
+		}
+	}
+}
+
+func TestGroup_Consumes_wildcard(t *testing.T) {
+	type testUser struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	r := newRouter()
+	newGroup(r, "/api").Consumes("*/*", xmlContentType).PATCH("/users", func(ctx *Context) {
+		var u testUser
+		if err := ctx.Bind(&u); err != nil {
+			ctx.WriteJSON(ErrorStatusCode(err, 400), err)
+			return
+		}
+
+		ctx.WriteString(200, "text/plain", u.Name)
+	})
+
+	// Bodies without a Content-Type are decoded as the first concrete media type
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("PATCH", "/api/users", strings.NewReader("<testUser><name>John Doe</name></testUser>")))
+	if w.Code != 200 || w.Body.String() != "John Doe" {
+		t.Fatalf("invalid response, received %d %q", w.Code, w.Body.String())
+	}
+
+	r = newRouter()
+	newGroup(r, "/api").Consumes(jsonContentType).PATCH("/users", func(ctx *Context) {})
+	req := httptest.NewRequest("PATCH", "/api/users", strings.NewReader("name=John+Doe"))
+	req.Header.Set("Content-Type", formContentType)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if accept := w.Header().Get("Accept-Patch"); w.Code != 415 || accept != jsonContentType {
+		t.Fatalf("invalid response, received %d with Accept-Patch %q", w.Code, accept)
+	}
+}
+
+func TestGroup_Produces(t *testing.T) {
+	type testUser struct {
+		Name string
+	}
+
+	r := newRouter()
+	var called bool
+	newGroup(r, "/api").Produces(xmlContentType).GET("/users", func(ctx *Context) {
+		called = true
+		ctx.Write(200, testUser{Name: "John Doe"})
+	})
+
+	tcs := []struct {
+		accept      string
+		statusCode  int
+		contentType string
+	}{
+		{accept: "", statusCode: 200, contentType: xmlContentType},
+		{accept: "application/json, application/xml;q=0.5", statusCode: 200, contentType: xmlContentType},
+		{accept: "*/*", statusCode: 200, contentType: xmlContentType},
+		{accept: "application/json", statusCode: 406},
+	}
+
+	for _, tc := range tcs {
+		called = false
+		req := httptest.NewRequest("GET", "/api/users", nil)
+		if len(tc.accept) > 0 {
+			req.Header.Set("Accept", tc.accept)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.statusCode {
+			t.Fatalf("invalid status code for %q, expected %d and received %d", tc.accept, tc.statusCode, w.Code)
+		}
+
+		if tc.statusCode == 406 {
+			if called {
+				t.Fatal("expected handler not to be called")
+			}
+
+			continue
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Fatalf("invalid content type, expected %q and received %q", tc.contentType, ct)
+		}
+	}
+}
+
+func TestRouter_Routes(t *testing.T) {
+	s := New()
+	s.GET("/health", func(ctx *Context) {})
+	api := s.Group("/api").Consumes("application/JSON").Produces(jsonContentType, "text/csv")
+	api.POST("/users", func(ctx *Context) {})
+	api.Group("/exports").GET("/users", func(ctx *Context) {})
+
+	expected := []RouteInfo{
+		{Method: "GET", Path: "/health", Consumes: []string{}, Produces: []string{}},
+		{Method: "GET", Path: "/api/exports/users", Consumes: []string{jsonContentType}, Produces: []string{jsonContentType, "text/csv"}},
+		{Method: "POST", Path: "/api/users", Consumes: []string{jsonContentType}, Produces: []string{jsonContentType, "text/csv"}},
+	}
+
+	routes := s.Routes()
+	for i := range routes {
+		if routes[i].Consumes == nil {
+			routes[i].Consumes = []string{}
+		}
+
+		if routes[i].Produces == nil {
+			routes[i].Produces = []string{}
+		}
+	}
+
+	if !reflect.DeepEqual(routes, expected) {
+		t.Fatalf("invalid routes, expected %+v and received %+v", expected, routes)
+	}
+}
@@ -1,6 +1,9 @@
 package httpserve
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
 // methodIndex maps HTTP method strings to a compact array index,
 // replacing the map[string]routes lookup with a direct array access.
@@ -14,7 +17,8 @@ const (
 	methodPUT                        // 4
 	methodDELETE                     // 5
 	methodOPTIONS                    // 6
-	numMethods    = 7
+	methodPATCH                      // 7
+	numMethods    = 8
 )
 
 func methodToIndex(m string) methodIndex {
@@ -31,7 +35,56 @@ func methodToIndex(m string) methodIndex {
 		return methodDELETE
 	case http.MethodOptions:
 		return methodOPTIONS
+	case http.MethodPatch:
+		return methodPATCH
 	default:
 		return methodUnknown
 	}
 }
+
+// String will return the HTTP method string for the index
+func (m methodIndex) String() string {
+	switch m {
+	case methodGET:
+		return http.MethodGet
+	case methodHEAD:
+		return http.MethodHead
+	case methodPOST:
+		return http.MethodPost
+	case methodPUT:
+		return http.MethodPut
+	case methodDELETE:
+		return http.MethodDelete
+	case methodOPTIONS:
+		return http.MethodOptions
+	case methodPATCH:
+		return http.MethodPatch
+	default:
+		return ""
+	}
+}
+
+// methodSet is a set of methods, stored as a bitmask of method indexes
+type methodSet uint8
+
+func (m *methodSet) add(idx methodIndex) {
+	*m |= 1 << idx
+}
+
+// String will return the comma separated methods of the set, in method index order
+func (m methodSet) String() string {
+	var sb strings.Builder
+	for idx := methodGET; idx < numMethods; idx++ {
+		if m&(1<<idx) == 0 {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteString(idx.String())
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,101 @@
+package httpserve
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const msgpackContentType = "application/msgpack"
+
+var _ Codec = &msgpackCodec{}
+
+// msgpackAPI is the MessagePack engine used throughout the package, configured alongside the JSON engine
+var msgpackAPI = newMsgpackEngine(JSONConfig{})
+
+func newMsgpackEngine(cfg JSONConfig) *msgpackEngine {
+	return &msgpackEngine{cfg: cfg}
+}
+
+// msgpackEngine encodes and decodes MessagePack
+//
+// Struct fields are named by their msgpack tag, falling back to their json
+// tag, so values are represented consistently with the JSON engine.
+type msgpackEngine struct {
+	cfg JSONConfig
+}
+
+func (m *msgpackEngine) Marshal(value interface{}) (bs []byte, err error) {
+	var buf bytes.Buffer
+	if err = m.Encode(&buf, value); err != nil {
+		return
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (m *msgpackEngine) Unmarshal(bs []byte, value interface{}) error {
+	return m.Decode(bytes.NewReader(bs), value)
+}
+
+func (m *msgpackEngine) Encode(w io.Writer, value interface{}) error {
+	enc := msgpack.GetEncoder()
+	defer msgpack.PutEncoder(enc)
+	enc.Reset(w)
+	enc.SetCustomStructTag("json")
+	enc.SetSortMapKeys(m.cfg.SortMapKeys)
+	enc.UseCompactInts(true)
+	return enc.Encode(value)
+}
+
+func (m *msgpackEngine) Decode(r io.Reader, value interface{}) error {
+	dec := msgpack.GetDecoder()
+	defer msgpack.PutDecoder(dec)
+	dec.Reset(r)
+	dec.SetCustomStructTag("json")
+	dec.DisallowUnknownFields(m.cfg.DisallowUnknownFields)
+	return dec.Decode(value)
+}
+
+type msgpackCodec struct{}
+
+func (m *msgpackCodec) Encode(w io.Writer, value interface{}) error {
+	return msgpackAPI.Encode(w, value)
+}
+
+func (m *msgpackCodec) Decode(r io.Reader, value interface{}) error {
+	return msgpackAPI.Decode(r, value)
+}
+
+// EncodeMsgpack will encode the JSON value as MessagePack, errors are encoded as structured Error objects
+func (j JSONValue) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(j.encoded())
+}
+
+// DecodeMsgpack will decode the JSON value from MessagePack, errors are decoded as *Error values
+//
+// When Data is set to a pointer prior to decoding, the data will be decoded into it.
+func (j *JSONValue) DecodeMsgpack(dec *msgpack.Decoder) (err error) {
+	var in struct {
+		Data   msgpack.RawMessage `json:"data"`
+		Errors []*Error           `json:"errors"`
+		Meta   Meta               `json:"meta"`
+	}
+
+	if err = dec.Decode(&in); err != nil {
+		return
+	}
+
+	if len(in.Data) > 0 && in.Data[0] != msgpackNil {
+		if err = j.decodeData(msgpackAPI.Unmarshal, in.Data); err != nil {
+			return
+		}
+	}
+
+	j.setDecoded(in.Errors, in.Meta)
+	return
+}
+
+// msgpackNil is the MessagePack encoding of nil
+const msgpackNil = 0xc0
@@ -0,0 +1,99 @@
+package httpserve
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+
+	"github.com/vroomy/httpserve/form"
+)
+
+const multipartContentType = "multipart/form-data"
+
+// MultipartConfig is the configuration for binding multipart/form-data bodies
+type MultipartConfig struct {
+	// MaxFileSize is the maximum size of a file, defaults to form.DefaultMaxFileSize
+	MaxFileSize int64
+	// MaxTotalSize is the maximum total size of the form, defaults to form.DefaultMaxMultipartSize
+	MaxTotalSize int64
+	// MemoryLimit is the size at which files are spilled to a temporary file, defaults to form.DefaultFileMemoryLimit
+	MemoryLimit int64
+	// AllowedTypes are the content types files are allowed to have (e.g. "image/*"), all types are allowed when empty
+	AllowedTypes []string
+}
+
+// BindMultipart will bind a multipart/form-data request body to the provided value
+//
+// Text parts are bound using the form struct tags, file parts are bound to
+// form.File, *form.File and []*form.File fields. Files are buffered in memory
+// up to the memory limit and to a temporary file beyond it, temporary files
+// are removed once the request has completed. Limits are set using
+// Serve.SetMultipart. Bind will also bind multipart bodies.
+func (c *Context) BindMultipart(value interface{}) (err error) {
+	if err = c.bindMultipart(value, nil); err != nil {
+		return
+	}
+
+	return c.autoValidate(value)
+}
+
+// StreamMultipart will bind the text parts of a multipart/form-data request body and stream each file part to the sink
+//
+// File parts are not buffered, the sink must consume each part before returning.
+// Note: Text parts following a file part are bound after the sink has been called for the file.
+func (c *Context) StreamMultipart(value interface{}, sink form.FileSink) (err error) {
+	if err = c.bindMultipart(value, sink); err != nil {
+		return
+	}
+
+	return c.autoValidate(value)
+}
+
+func (c *Context) bindMultipart(value interface{}, sink form.FileSink) (err error) {
+	defer c.request.Body.Close()
+	var boundary string
+	if boundary, err = form.ParseBoundary(c.request.Header.Get("Content-Type")); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+	}
+
+	var body io.Reader
+	if body, err = c.bodyReader(); err != nil {
+		return
+	}
+
+	dec := form.NewMultipartDecoder(body, boundary)
+	if c.router != nil {
+		cfg := &c.router.multipart
+		if cfg.MaxFileSize > 0 {
+			dec.SetMaxFileSize(cfg.MaxFileSize)
+		}
+
+		if cfg.MaxTotalSize > 0 {
+			dec.SetMaxTotalSize(cfg.MaxTotalSize)
+		}
+
+		if cfg.MemoryLimit > 0 {
+			dec.SetMemoryLimit(cfg.MemoryLimit)
+		}
+
+		dec.SetAllowedTypes(cfg.AllowedTypes...)
+	}
+
+	dec.SetFileSink(sink)
+	// Temporary files are removed when the context is released
+	c.multipart = append(c.multipart, dec)
+
+	err = dec.Decode(value)
+	if errors.Is(err, form.ErrFileTooLarge) || errors.Is(err, form.ErrMultipartTooLarge) {
+		return fmt.Errorf("%w: %w", ErrRequestEntityTooLarge, err)
+	}
+
+	return formFieldErrors("body", err)
+}
+
+// isMultipart will return whether or not the request body is multipart/form-data
+func (c *Context) isMultipart() bool {
+	mediaType, _, _ := mime.ParseMediaType(c.request.Header.Get("Content-Type"))
+	return mediaType == multipartContentType
+}
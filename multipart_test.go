@@ -0,0 +1,118 @@
+package httpserve
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/vroomy/httpserve/form"
+)
+
+func TestContext_BindMultipart(t *testing.T) {
+	type testForm struct {
+		Name string     `form:"name"`
+		File *form.File `form:"file"`
+	}
+
+	r := newRouter()
+	r.SetMultipart(MultipartConfig{MemoryLimit: 4})
+
+	req := newTestMultipartRequest(t, "name", "John Doe", "file", "hello world")
+	ctx := acquireContext(httptest.NewRecorder(), req)
+	ctx.router = r
+
+	var tf testForm
+	if err := ctx.Bind(&tf); err != nil {
+		t.Fatal(err)
+	}
+
+	if tf.Name != "John Doe" || tf.File == nil || tf.File.Filename != "file.txt" {
+		t.Fatalf("invalid value: %+v", tf)
+	}
+
+	bs, err := io.ReadAll(tf.File.Open())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(bs) != "hello world" {
+		t.Fatalf("invalid file contents, expected %q and received %q", "hello world", string(bs))
+	}
+
+	tmp, ok := tf.File.Open().(*io.SectionReader)
+	if !ok {
+		t.Fatal("expected file to be buffered to a temporary file")
+	}
+
+	f, _, _ := tmp.Outer()
+	name := f.(*os.File).Name()
+	releaseContext(ctx)
+	if _, err = os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("expected temporary file to be removed, received %v", err)
+	}
+}
+
+func TestContext_StreamMultipart(t *testing.T) {
+	type testForm struct {
+		Name string `form:"name"`
+	}
+
+	var sunk bytes.Buffer
+	var tf testForm
+	ctx := newContext(httptest.NewRecorder(), newTestMultipartRequest(t, "name", "John Doe", "file", "hello world"), nil)
+	if err := ctx.StreamMultipart(&tf, func(part *form.FilePart) (err error) {
+		_, err = io.Copy(&sunk, part)
+		return
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if tf.Name != "John Doe" || sunk.String() != "hello world" {
+		t.Fatalf("invalid result, sunk %q into %+v", sunk.String(), tf)
+	}
+
+	r := newRouter()
+	r.SetMultipart(MultipartConfig{MaxFileSize: 4})
+	ctx = newContext(httptest.NewRecorder(), newTestMultipartRequest(t, "name", "John Doe", "file", "hello world"), nil)
+	ctx.router = r
+	err := ctx.BindMultipart(&struct {
+		File *form.File `form:"file"`
+	}{})
+	if !errors.Is(err, ErrRequestEntityTooLarge) || !errors.Is(err, form.ErrFileTooLarge) {
+		t.Fatalf("expected ErrRequestEntityTooLarge, received %v", err)
+	}
+
+	if code := ErrorStatusCode(err, 400); code != 413 {
+		t.Fatalf("invalid status code, expected %d and received %d", 413, code)
+	}
+}
+
+func newTestMultipartRequest(t *testing.T, key, value, field, contents string) *http.Request {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField(key, value); err != nil {
+		t.Fatal(err)
+	}
+
+	fw, err := w.CreateFormFile(field, field+".txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = io.WriteString(fw, contents); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("POST", "/", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
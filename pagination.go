@@ -0,0 +1,285 @@
+package httpserve
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageLimit    = 20
+	defaultPageMaxLimit = 100
+)
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or has an invalid signature
+var ErrInvalidCursor error = newStatusError(400, "invalid cursor")
+
+// errInvalidPageParam is the error of a limit or offset which is not a non-negative integer
+var errInvalidPageParam = errors.New("must be a non-negative integer")
+
+// defaultCursorSecret is used to sign cursors when no secret is configured
+var defaultCursorSecret = newCursorSecret()
+
+// PaginationConfig is the configuration for the Context pagination helpers
+type PaginationConfig struct {
+	// DefaultLimit is the limit used when none is requested, defaults to 20
+	DefaultLimit int
+	// MaxLimit is the maximum limit, greater limits are clamped, defaults to 100
+	MaxLimit int
+	// Secret is the key cursors are signed with
+	//
+	// When unset, a random key is generated on startup. Cursors will then not
+	// be valid across restarts or between multiple instances of the server.
+	Secret []byte
+}
+
+func (p *PaginationConfig) defaultLimit() int {
+	if p.DefaultLimit <= 0 {
+		return defaultPageLimit
+	}
+
+	return p.DefaultLimit
+}
+
+func (p *PaginationConfig) maxLimit() int {
+	if p.MaxLimit <= 0 {
+		return defaultPageMaxLimit
+	}
+
+	return p.MaxLimit
+}
+
+func (p *PaginationConfig) secret() []byte {
+	if len(p.Secret) == 0 {
+		return defaultCursorSecret
+	}
+
+	return p.Secret
+}
+
+// Page is the pagination of a request, bound from the limit, offset and cursor query parameters
+type Page struct {
+	// Limit is the number of items requested, clamped to the configured bounds
+	Limit int
+	// Offset is the number of items to skip for offset pagination
+	Offset int
+	// Cursor is the opaque cursor for cursor pagination, decode it using Context.DecodeCursor
+	Cursor string
+
+	url *url.URL
+}
+
+// OffsetInfo will return the page info for offset pagination, using the total number of items
+func (p *Page) OffsetInfo(total int) (info PageInfo) {
+	info.Limit = p.Limit
+	info.Offset = p.Offset
+	info.Total = &total
+	info.First = p.link(0, "")
+	if next := p.Offset + p.Limit; next < total {
+		info.Next = p.link(next, "")
+	}
+
+	if p.Offset > 0 {
+		prev := p.Offset - p.Limit
+		if prev < 0 {
+			prev = 0
+		}
+
+		info.Prev = p.link(prev, "")
+	}
+
+	return
+}
+
+// CursorInfo will return the page info for cursor pagination, empty cursors are omitted
+func (p *Page) CursorInfo(next, prev string) (info PageInfo) {
+	info.Limit = p.Limit
+	info.First = p.link(0, "")
+	if len(next) > 0 {
+		info.Next = p.link(0, next)
+	}
+
+	if len(prev) > 0 {
+		info.Prev = p.link(0, prev)
+	}
+
+	return
+}
+
+// link will return the request URL for the provided offset or cursor, retaining all other query parameters
+func (p *Page) link(offset int, cursor string) string {
+	var u url.URL
+	if p.url != nil {
+		u = *p.url
+	}
+
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(p.Limit))
+	query.Del("offset")
+	query.Del("cursor")
+	switch {
+	case len(cursor) > 0:
+		query.Set("cursor", cursor)
+	case offset > 0:
+		query.Set("offset", strconv.Itoa(offset))
+	}
+
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// PageInfo contains the links and metadata of a paginated response
+type PageInfo struct {
+	Limit  int
+	Offset int
+	// Total is the total number of items, nil when unknown
+	Total *int
+
+	First string
+	Next  string
+	Prev  string
+}
+
+// Meta will return the page info as response meta
+func (p *PageInfo) Meta() (meta Meta) {
+	meta = Meta{"limit": p.Limit}
+	if p.Offset > 0 {
+		meta["offset"] = p.Offset
+	}
+
+	if p.Total != nil {
+		meta["total"] = *p.Total
+	}
+
+	for key, link := range map[string]string{"first": p.First, "next": p.Next, "prev": p.Prev} {
+		if len(link) > 0 {
+			meta[key] = link
+		}
+	}
+
+	return
+}
+
+// LinkHeader will return the RFC 8288 Link header value for the page info
+func (p *PageInfo) LinkHeader() string {
+	var links []string
+	for _, l := range [...]struct{ rel, url string }{{"next", p.Next}, {"prev", p.Prev}, {"first", p.First}} {
+		if len(l.url) > 0 {
+			links = append(links, "<"+l.url+`>; rel="`+l.rel+`"`)
+		}
+	}
+
+	return strings.Join(links, ", ")
+}
+
+// Page will return the pagination of the request, bound from the limit, offset and cursor query parameters
+//
+// Limits are clamped to the bounds set by Serve.SetPagination, invalid
+// parameters are returned as FieldErrors.
+func (c *Context) Page() (p Page, err error) {
+	cfg := c.paginationConfig()
+	query := c.request.URL.Query()
+	p.url = c.request.URL
+	p.Limit = cfg.defaultLimit()
+	p.Cursor = query.Get("cursor")
+
+	var errs FieldErrors
+	if raw := query.Get("limit"); len(raw) > 0 {
+		var limit int
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+			errs = append(errs, &FieldError{Source: "query", Field: "limit", Value: raw, Code: "invalid", Err: errInvalidPageParam})
+		} else if limit > 0 {
+			p.Limit = min(limit, cfg.maxLimit())
+		}
+	}
+
+	if raw := query.Get("offset"); len(raw) > 0 {
+		if p.Offset, err = strconv.Atoi(raw); err != nil || p.Offset < 0 {
+			p.Offset = 0
+			errs = append(errs, &FieldError{Source: "query", Field: "offset", Value: raw, Code: "invalid", Err: errInvalidPageParam})
+		}
+	}
+
+	return p, errs.err()
+}
+
+// WritePage will write a paginated JSON response, including the page info as meta and within the Link header
+func (c *Context) WritePage(statusCode int, value interface{}, info PageInfo) {
+	if link := info.LinkHeader(); len(link) > 0 {
+		c.writer.Header().Set("Link", link)
+	}
+
+	c.WriteJSONWithMeta(statusCode, value, info.Meta())
+}
+
+// EncodeCursor will encode a value as an opaque cursor, signed using the pagination secret
+func (c *Context) EncodeCursor(value interface{}) (cursor string, err error) {
+	var bs []byte
+	if bs, err = jsonAPI.Marshal(value); err != nil {
+		return
+	}
+
+	cfg := c.paginationConfig()
+	payload := base64.RawURLEncoding.EncodeToString(bs)
+	signature := base64.RawURLEncoding.EncodeToString(signCursor(cfg.secret(), payload))
+	return payload + "." + signature, nil
+}
+
+// DecodeCursor will decode a cursor created by EncodeCursor into the provided value
+//
+// ErrInvalidCursor is returned when the cursor is malformed or has been tampered with.
+func (c *Context) DecodeCursor(cursor string, value interface{}) (err error) {
+	payload, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+
+	var sig []byte
+	if sig, err = base64.RawURLEncoding.DecodeString(signature); err != nil {
+		return ErrInvalidCursor
+	}
+
+	cfg := c.paginationConfig()
+	if !hmac.Equal(sig, signCursor(cfg.secret(), payload)) {
+		return ErrInvalidCursor
+	}
+
+	var bs []byte
+	if bs, err = base64.RawURLEncoding.DecodeString(payload); err != nil {
+		return ErrInvalidCursor
+	}
+
+	if err = jsonAPI.Unmarshal(bs, value); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return
+}
+
+func (c *Context) paginationConfig() (cfg *PaginationConfig) {
+	if c.router == nil {
+		return &PaginationConfig{}
+	}
+
+	return &c.router.pagination
+}
+
+func signCursor(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func newCursorSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+
+	return secret
+}
@@ -0,0 +1,124 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_Page(t *testing.T) {
+	tcs := []struct {
+		name   string
+		query  string
+		limit  int
+		offset int
+		cursor string
+		err    string
+	}{
+		{name: "defaults", query: "", limit: 10},
+		{name: "limit and offset", query: "?limit=5&offset=15", limit: 5, offset: 15},
+		{name: "clamped limit", query: "?limit=500", limit: 50},
+		{name: "cursor", query: "?cursor=abc", limit: 10, cursor: "abc"},
+		{name: "invalid limit", query: "?limit=foo", limit: 10, err: "limit: must be a non-negative integer"},
+		{name: "negative offset", query: "?offset=-1", limit: 10, err: "offset: must be a non-negative integer"},
+	}
+
+	r := newRouter()
+	r.SetPagination(PaginationConfig{DefaultLimit: 10, MaxLimit: 50})
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/items"+tc.query, nil), nil)
+			ctx.router = r
+			p, err := ctx.Page()
+			if (err != nil) != (len(tc.err) > 0) || (err != nil && err.Error() != tc.err) {
+				t.Fatalf("invalid error, expected %q and received %v", tc.err, err)
+			}
+
+			if p.Limit != tc.limit || p.Offset != tc.offset || p.Cursor != tc.cursor {
+				t.Fatalf("invalid page, received %+v", p)
+			}
+		})
+	}
+}
+
+func TestPage_OffsetInfo_zero(t *testing.T) {
+	var p Page
+	p.Limit = 10
+	info := p.OffsetInfo(25)
+	if info.First != "/?limit=10" || info.Next != "/?limit=10&offset=10" {
+		t.Fatalf("invalid page info, received %+v", info)
+	}
+}
+
+func TestContext_WritePage(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/items?limit=10&offset=20&sort=name", nil), nil)
+	p, err := ctx.Page()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.WritePage(200, []int{1}, p.OffsetInfo(35))
+
+	expectedLink := `</items?limit=10&offset=30&sort=name>; rel="next", </items?limit=10&offset=10&sort=name>; rel="prev", </items?limit=10&sort=name>; rel="first"`
+	if link := w.Header().Get("Link"); link != expectedLink {
+		t.Fatalf("invalid Link header, expected %q and received %q", expectedLink, link)
+	}
+
+	assertJSONEqual(t, `{"data":[1],"meta":{"limit":10,"offset":20,"total":35,"first":"/items?limit=10&sort=name","next":"/items?limit=10&offset=30&sort=name","prev":"/items?limit=10&offset=10&sort=name"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	ctx = newContext(w, httptest.NewRequest("GET", "/items?limit=10&offset=30", nil), nil)
+	if p, err = ctx.Page(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.WritePage(200, []int{1}, p.OffsetInfo(35))
+	expectedLink = `</items?limit=10&offset=20>; rel="prev", </items?limit=10>; rel="first"`
+	if link := w.Header().Get("Link"); link != expectedLink {
+		t.Fatalf("invalid Link header, expected %q and received %q", expectedLink, link)
+	}
+}
+
+func TestContext_EncodeCursor(t *testing.T) {
+	type cursor struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	r := newRouter()
+	r.SetPagination(PaginationConfig{Secret: []byte("secret")})
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/items", nil), nil)
+	ctx.router = r
+
+	encoded, err := ctx.EncodeCursor(cursor{ID: 3, Name: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded cursor
+	if err = ctx.DecodeCursor(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.ID != 3 || decoded.Name != "foo" {
+		t.Fatalf("invalid cursor, received %+v", decoded)
+	}
+
+	p := Page{Limit: 5, url: ctx.request.URL}
+	info := p.CursorInfo(encoded, "")
+	if expected := "/items?cursor=" + encoded + "&limit=5"; info.Next != expected {
+		t.Fatalf("invalid next link, expected %q and received %q", expected, info.Next)
+	}
+
+	for _, invalid := range []string{"", "foo", encoded[:len(encoded)-2], "e30." + encoded[len(encoded)-10:]} {
+		if err = ctx.DecodeCursor(invalid, &decoded); !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("expected ErrInvalidCursor for %q, received %v", invalid, err)
+		}
+	}
+
+	r.SetPagination(PaginationConfig{Secret: []byte("rotated")})
+	if err = ctx.DecodeCursor(encoded, &decoded); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for a cursor signed with a different secret, received %v", err)
+	}
+}
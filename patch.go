@@ -0,0 +1,566 @@
+package httpserve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JSONPatchContentType is the content type of an RFC 6902 JSON Patch document
+	JSONPatchContentType = "application/json-patch+json"
+	// MergePatchContentType is the content type of an RFC 7396 JSON Merge Patch document
+	MergePatchContentType = "application/merge-patch+json"
+)
+
+var (
+	// ErrPatchTestFailed is returned when a JSON Patch test operation does not match
+	ErrPatchTestFailed error = newStatusError(409, "patch test operation failed")
+	// ErrPatchPathNotFound is returned when a JSON Patch operation references a location which does not exist
+	ErrPatchPathNotFound error = newStatusError(422, "patch path not found")
+	// ErrInvalidPatch is returned when a patch document is malformed
+	ErrInvalidPatch error = newStatusError(400, "invalid patch")
+)
+
+// ApplyPatch will apply the JSON Patch or JSON Merge Patch request body to the provided value
+//
+// The patch type is determined by the request Content-Type, other content types
+// return ErrUnsupportedMediaType. The value must be a pointer, it is only
+// modified when every patch operation succeeds.
+func (c *Context) ApplyPatch(value interface{}) (err error) {
+	rval := reflect.ValueOf(value)
+	if rval.Kind() != reflect.Ptr || rval.IsNil() {
+		return ErrInvalidBindValue
+	}
+
+	var doc []byte
+	if doc, err = jsonAPI.Marshal(value); err != nil {
+		return
+	}
+
+	if doc, err = c.ApplyPatchDocument(doc); err != nil {
+		return
+	}
+
+	// Unmarshal into a copy of the value with its JSON fields zeroed, so fields
+	// removed by the patch are reset while fields JSON cannot see (unexported or
+	// tagged json:"-") are kept.
+	patched := reflect.New(rval.Elem().Type())
+	patched.Elem().Set(rval.Elem())
+	resetJSONFields(patched.Elem())
+	if err = jsonAPI.Unmarshal(doc, patched.Interface()); err != nil {
+		return
+	}
+
+	if err = c.autoValidate(patched.Interface()); err != nil {
+		return
+	}
+
+	rval.Elem().Set(patched.Elem())
+	return
+}
+
+// resetJSONFields will zero the fields of a value which are represented in JSON
+func resetJSONFields(rval reflect.Value) {
+	if rval.Kind() != reflect.Struct {
+		rval.SetZero()
+		return
+	}
+
+	rtype := rval.Type()
+	for i := 0; i < rtype.NumField(); i++ {
+		sf := rtype.Field(i)
+		tag := sf.Tag.Get("json")
+		field := rval.Field(i)
+		switch {
+		case tag == "-" || !field.CanSet():
+			// Field is not represented in JSON
+		case sf.Anonymous && len(tag) == 0 && sf.Type.Kind() == reflect.Struct:
+			// Fields of embedded structs are promoted
+			resetJSONFields(field)
+		default:
+			field.SetZero()
+		}
+	}
+}
+
+// ApplyPatchDocument will apply the JSON Patch or JSON Merge Patch request body to a JSON document
+//
+// The patch type is determined by the request Content-Type, other content types
+// return ErrUnsupportedMediaType.
+func (c *Context) ApplyPatchDocument(doc []byte) (patched []byte, err error) {
+	mediaType, _, _ := mime.ParseMediaType(c.request.Header.Get("Content-Type"))
+	if mediaType != JSONPatchContentType && mediaType != MergePatchContentType {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	defer c.request.Body.Close()
+	var body io.Reader
+	if body, err = c.bodyReader(); err != nil {
+		return
+	}
+
+	var patch []byte
+	if patch, err = io.ReadAll(body); err != nil {
+		return
+	}
+
+	if mediaType == MergePatchContentType {
+		return ApplyMergePatch(doc, patch)
+	}
+
+	return ApplyJSONPatch(doc, patch)
+}
+
+// ApplyJSONPatch will apply an RFC 6902 JSON Patch to a JSON document
+//
+// Operations are applied atomically, the document is left unchanged when any
+// operation fails. Failures are returned as *PatchError values.
+func ApplyJSONPatch(doc, patch []byte) (patched []byte, err error) {
+	var ops []PatchOperation
+	if err = json.Unmarshal(patch, &ops); err != nil {
+		return nil, &PatchError{Index: -1, Err: fmt.Errorf("%w: %v", ErrInvalidPatch, err)}
+	}
+
+	var root interface{}
+	if root, err = decodeJSONDocument(doc); err != nil {
+		return
+	}
+
+	for i, op := range ops {
+		if root, err = op.apply(root); err != nil {
+			return nil, &PatchError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+	}
+
+	return jsonAPI.Marshal(root)
+}
+
+// ApplyMergePatch will apply an RFC 7396 JSON Merge Patch to a JSON document
+func ApplyMergePatch(doc, patch []byte) (patched []byte, err error) {
+	var p interface{}
+	if p, err = decodeJSONDocument(patch); err != nil {
+		return nil, &PatchError{Index: -1, Err: fmt.Errorf("%w: %v", ErrInvalidPatch, err)}
+	}
+
+	var root interface{}
+	if root, err = decodeJSONDocument(doc); err != nil {
+		return
+	}
+
+	return jsonAPI.Marshal(mergePatch(root, p))
+}
+
+// PatchOperation is a single RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func (p *PatchOperation) apply(root interface{}) (out interface{}, err error) {
+	var path []string
+	if path, err = parsePointer(p.Path); err != nil {
+		return
+	}
+
+	switch p.Op {
+	case "add":
+		var value interface{}
+		if value, err = p.value(); err != nil {
+			return
+		}
+
+		return addValue(root, path, value)
+	case "remove":
+		out, _, err = removeValue(root, path)
+		return
+	case "replace":
+		var value interface{}
+		if value, err = p.value(); err != nil {
+			return
+		}
+
+		if root, _, err = removeValue(root, path); err != nil {
+			return
+		}
+
+		return addValue(root, path, value)
+	case "move", "copy":
+		var from []string
+		if from, err = parsePointer(p.From); err != nil {
+			return
+		}
+
+		var value interface{}
+		if p.Op == "move" {
+			if isPrefix(from, path) {
+				return nil, fmt.Errorf("%w: cannot move a value into one of its children", ErrInvalidPatch)
+			}
+
+			if root, value, err = removeValue(root, from); err != nil {
+				return
+			}
+		} else if value, err = getValue(root, from); err != nil {
+			return
+		} else {
+			value = deepCopy(value)
+		}
+
+		return addValue(root, path, value)
+	case "test":
+		var expected, actual interface{}
+		if expected, err = p.value(); err != nil {
+			return
+		}
+
+		if actual, err = getValue(root, path); err != nil {
+			return
+		}
+
+		if !jsonEqual(expected, actual) {
+			return nil, ErrPatchTestFailed
+		}
+
+		return root, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported operation \"%s\"", ErrInvalidPatch, p.Op)
+	}
+}
+
+func (p *PatchOperation) value() (value interface{}, err error) {
+	if len(p.Value) == 0 {
+		return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+	}
+
+	return decodeJSONDocument(p.Value)
+}
+
+// PatchError is an error encountered while applying a patch
+type PatchError struct {
+	// Index is the index of the failed operation, -1 when the patch document itself is invalid
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+// Error will return the error message
+func (p *PatchError) Error() string {
+	if p.Index < 0 {
+		return p.Err.Error()
+	}
+
+	return fmt.Sprintf("operation %d (%s %s): %v", p.Index, p.Op, p.Path, p.Err)
+}
+
+// Unwrap will return the underlying error
+func (p *PatchError) Unwrap() error {
+	return p.Err
+}
+
+// ErrorCode will return the error code
+func (p *PatchError) ErrorCode() string {
+	switch {
+	case errors.Is(p.Err, ErrPatchTestFailed):
+		return "patch_test_failed"
+	case errors.Is(p.Err, ErrPatchPathNotFound):
+		return "patch_path_not_found"
+	default:
+		return "invalid_patch"
+	}
+}
+
+// ErrorField will return the path of the failed operation
+func (p *PatchError) ErrorField() string {
+	return p.Path
+}
+
+// ErrorDetails will return the index and operation of the failed operation
+func (p *PatchError) ErrorDetails() interface{} {
+	if p.Index < 0 {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"index": p.Index,
+		"op":    p.Op,
+	}
+}
+
+func decodeJSONDocument(bs []byte) (value interface{}, err error) {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	// Numbers are decoded as json.Number to avoid precision loss
+	dec.UseNumber()
+	err = dec.Decode(&value)
+	return
+}
+
+// parsePointer will parse an RFC 6901 JSON Pointer into its reference tokens
+func parsePointer(pointer string) (path []string, err error) {
+	if len(pointer) == 0 {
+		return
+	}
+
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: invalid JSON pointer \"%s\"", ErrInvalidPatch, pointer)
+	}
+
+	path = strings.Split(pointer[1:], "/")
+	for i, token := range path {
+		path[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+
+	for i, token := range prefix {
+		if path[i] != token {
+			return false
+		}
+	}
+
+	return true
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (index int, err error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+
+	if index, err = strconv.Atoi(token); err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index \"%s\"", ErrInvalidPatch, token)
+	}
+
+	max := length - 1
+	if allowEnd {
+		max = length
+	}
+
+	if index > max {
+		return 0, fmt.Errorf("%w: array index %d out of bounds", ErrPatchPathNotFound, index)
+	}
+
+	return
+}
+
+func getValue(root interface{}, path []string) (value interface{}, err error) {
+	value = root
+	for _, token := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, ErrPatchPathNotFound
+			}
+		case []interface{}:
+			var i int
+			if i, err = arrayIndex(token, len(v), false); err != nil {
+				return
+			}
+
+			value = v[i]
+		default:
+			return nil, ErrPatchPathNotFound
+		}
+	}
+
+	return
+}
+
+// addValue will add a value at the provided path, returning the new root
+func addValue(root interface{}, path []string, value interface{}) (out interface{}, err error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	var parent interface{}
+	if parent, err = getValue(root, path[:len(path)-1]); err != nil {
+		return
+	}
+
+	token := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[token] = value
+		return root, nil
+	case []interface{}:
+		var i int
+		if i, err = arrayIndex(token, len(p), true); err != nil {
+			return
+		}
+
+		p = append(p, nil)
+		copy(p[i+1:], p[i:])
+		p[i] = value
+		return setValue(root, path[:len(path)-1], p)
+	default:
+		return nil, ErrPatchPathNotFound
+	}
+}
+
+// removeValue will remove the value at the provided path, returning the new root and the removed value
+func removeValue(root interface{}, path []string) (out, removed interface{}, err error) {
+	if len(path) == 0 {
+		return nil, root, nil
+	}
+
+	var parent interface{}
+	if parent, err = getValue(root, path[:len(path)-1]); err != nil {
+		return
+	}
+
+	token := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		var ok bool
+		if removed, ok = p[token]; !ok {
+			return nil, nil, ErrPatchPathNotFound
+		}
+
+		delete(p, token)
+		return root, removed, nil
+	case []interface{}:
+		var i int
+		if i, err = arrayIndex(token, len(p), false); err != nil {
+			return
+		}
+
+		removed = p[i]
+		p = append(p[:i:i], p[i+1:]...)
+		out, err = setValue(root, path[:len(path)-1], p)
+		return
+	default:
+		return nil, nil, ErrPatchPathNotFound
+	}
+}
+
+// setValue will replace the value at an existing path, used to store resized arrays
+func setValue(root interface{}, path []string, value interface{}) (out interface{}, err error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	var parent interface{}
+	if parent, err = getValue(root, path[:len(path)-1]); err != nil {
+		return
+	}
+
+	token := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[token] = value
+	case []interface{}:
+		var i int
+		if i, err = arrayIndex(token, len(p), false); err != nil {
+			return
+		}
+
+		p[i] = value
+	}
+
+	return root, nil
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+
+		t[key] = mergePatch(t[key], value)
+	}
+
+	return t
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = deepCopy(val)
+		}
+
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = deepCopy(val)
+		}
+
+		return s
+	default:
+		return v
+	}
+}
+
+// jsonEqual will compare two decoded JSON values, numbers are compared numerically
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+
+		if av == bv {
+			return true
+		}
+
+		af, aerr := av.Float64()
+		bf, berr := bv.Float64()
+		return aerr == nil && berr == nil && af == bf
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for key, val := range av {
+			if other, ok := bv[key]; !ok || !jsonEqual(val, other) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,132 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := `{"name":"John Doe","age":33,"tags":["a","b"],"address":{"city":"Phoenix"}}`
+	tcs := []struct {
+		name     string
+		patch    string
+		expected string
+		err      error
+	}{
+		{
+			name:     "add, replace and remove",
+			patch:    `[{"op":"add","path":"/tags/1","value":"c"},{"op":"replace","path":"/age","value":34},{"op":"remove","path":"/address/city"}]`,
+			expected: `{"address":{},"age":34,"name":"John Doe","tags":["a","c","b"]}`,
+		},
+		{
+			name:     "move, copy and test",
+			patch:    `[{"op":"test","path":"/age","value":33.0},{"op":"copy","from":"/name","path":"/alias"},{"op":"move","from":"/tags/0","path":"/tags/-"}]`,
+			expected: `{"address":{"city":"Phoenix"},"age":33,"alias":"John Doe","name":"John Doe","tags":["b","a"]}`,
+		},
+		{
+			name:  "failed test",
+			patch: `[{"op":"replace","path":"/age","value":34},{"op":"test","path":"/name","value":"Jane Doe"}]`,
+			err:   ErrPatchTestFailed,
+		},
+		{
+			name:  "missing path",
+			patch: `[{"op":"remove","path":"/address/zip"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "invalid patch",
+			patch: `{"op":"remove"}`,
+			err:   ErrInvalidPatch,
+		},
+	}
+
+	for _, tc := range tcs {
+		patched, err := ApplyJSONPatch([]byte(doc), []byte(tc.patch))
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%s: invalid error, expected %v and received %v", tc.name, tc.err, err)
+		}
+
+		if tc.err != nil {
+			continue
+		}
+
+		if normalized := mustNormalizeJSON(t, patched); normalized != tc.expected {
+			t.Fatalf("%s: invalid document, expected %s and received %s", tc.name, tc.expected, normalized)
+		}
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	doc := `{"name":"John Doe","age":33,"address":{"city":"Phoenix","zip":"85001"}}`
+	patch := `{"age":null,"address":{"zip":null,"state":"AZ"},"tags":["a"]}`
+	patched, err := ApplyMergePatch([]byte(doc), []byte(patch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"address":{"city":"Phoenix","state":"AZ"},"name":"John Doe","tags":["a"]}`
+	if normalized := mustNormalizeJSON(t, patched); normalized != expected {
+		t.Fatalf("invalid document, expected %s and received %s", expected, normalized)
+	}
+}
+
+func TestContext_ApplyPatch(t *testing.T) {
+	ts := TestJSONStruct{Name: "John Doe", Age: 33}
+	req := httptest.NewRequest("PATCH", "/", strings.NewReader(`[{"op":"replace","path":"/age","value":34},{"op":"test","path":"/name","value":"Jane"}]`))
+	req.Header.Set("Content-Type", JSONPatchContentType)
+	if err := newContext(httptest.NewRecorder(), req, nil).ApplyPatch(&ts); ErrorStatusCode(err, 400) != 409 {
+		t.Fatalf("invalid error, expected a 409 error and received %v", err)
+	}
+
+	if ts.Age != 33 {
+		t.Fatalf("expected value to be unchanged, received %#v", ts)
+	}
+
+	req = httptest.NewRequest("PATCH", "/", strings.NewReader(`{"age":34}`))
+	req.Header.Set("Content-Type", MergePatchContentType)
+	if err := newContext(httptest.NewRecorder(), req, nil).ApplyPatch(&ts); err != nil {
+		t.Fatal(err)
+	}
+
+	if ts.Name != "John Doe" || ts.Age != 34 {
+		t.Fatalf("invalid value, received %#v", ts)
+	}
+}
+
+func TestContext_ApplyPatch_hidden_fields(t *testing.T) {
+	type testUser struct {
+		Name     string `json:"name"`
+		Email    string `json:"email,omitempty"`
+		Password string `json:"-"`
+		version  int
+	}
+
+	u := testUser{Name: "John Doe", Email: "john@example.com", Password: "hash", version: 3}
+	req := httptest.NewRequest("PATCH", "/", strings.NewReader(`{"name":"Jane Doe","email":null}`))
+	req.Header.Set("Content-Type", MergePatchContentType)
+	if err := newContext(httptest.NewRecorder(), req, nil).ApplyPatch(&u); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := testUser{Name: "Jane Doe", Password: "hash", version: 3}
+	if u != expected {
+		t.Fatalf("invalid value, expected %#v and received %#v", expected, u)
+	}
+}
+
+func mustNormalizeJSON(t *testing.T, bs []byte) string {
+	value, err := decodeJSONDocument(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// encoding/json sorts map keys, providing a stable representation
+	normalized, err := (&stdJSON{}).Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(normalized)
+}
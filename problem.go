@@ -0,0 +1,180 @@
+package httpserve
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	// problemTypeBlank is the default problem type as defined by RFC 9457
+	problemTypeBlank = "about:blank"
+)
+
+// NewProblem will return a new Problem for a given status code and error
+//
+// When the error is (or wraps) a *Problem, it will be used as the base of the
+// returned Problem. Otherwise the error message is used as the problem detail.
+func NewProblem(statusCode int, err error) *Problem {
+	var p Problem
+	if pp := (*Problem)(nil); errors.As(err, &pp) {
+		p = *pp
+	} else if err != nil {
+		p.Detail = err.Error()
+	}
+
+	if p.Status == 0 {
+		p.Status = statusCode
+	}
+
+	if len(p.Title) == 0 {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	return &p
+}
+
+// newProblemFromValue will create a Problem from an error response value
+func newProblemFromValue(statusCode int, value interface{}) (p *Problem, ok bool) {
+	switch v := value.(type) {
+	case *Problem:
+		return v, true
+	case FieldErrors:
+		p = NewProblem(statusCode, v)
+		p.Set("errors", newErrors(v.errors()))
+		return p, true
+	case error:
+		return NewProblem(statusCode, v), true
+	case []error:
+		p = NewProblem(statusCode, errors.Join(v...))
+		p.Set("errors", newErrors(v))
+		return p, true
+	default:
+		return nil, false
+	}
+}
+
+// Problem represents an RFC 9457 problem details object
+type Problem struct {
+	// Type is a URI reference identifying the problem type
+	Type string
+	// Title is a short, human-readable summary of the problem type
+	Title string
+	// Status is the HTTP status code generated by the origin server
+	Status int
+	// Detail is a human-readable explanation specific to this occurrence
+	Detail string
+	// Instance is a URI reference identifying this specific occurrence
+	Instance string
+
+	// Extensions are additional members included with the problem
+	Extensions map[string]interface{}
+}
+
+// Set will set an extension member for the Problem
+func (p *Problem) Set(key string, value interface{}) {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+
+	p.Extensions[key] = value
+}
+
+// StatusCode will return the status code of the Problem
+func (p *Problem) StatusCode() int {
+	return p.Status
+}
+
+// Error will return the Problem as an error message
+func (p *Problem) Error() string {
+	switch {
+	case len(p.Detail) == 0:
+		return p.Title
+	case len(p.Title) == 0:
+		return p.Detail
+	default:
+		return p.Title + ": " + p.Detail
+	}
+}
+
+// MarshalJSON will marshal the Problem as JSON, extension members are flattened into the top-level object
+func (p *Problem) MarshalJSON() (bs []byte, err error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		m[key] = value
+	}
+
+	m["type"] = p.Type
+	if len(p.Type) == 0 {
+		m["type"] = problemTypeBlank
+	}
+
+	if len(p.Title) > 0 {
+		m["title"] = p.Title
+	}
+
+	if p.Status > 0 {
+		m["status"] = p.Status
+	}
+
+	if len(p.Detail) > 0 {
+		m["detail"] = p.Detail
+	}
+
+	if len(p.Instance) > 0 {
+		m["instance"] = p.Instance
+	}
+
+	return jsonAPI.Marshal(m)
+}
+
+// UnmarshalJSON will unmarshal a Problem from JSON, unknown members are stored as extensions
+func (p *Problem) UnmarshalJSON(bs []byte) (err error) {
+	var m map[string]interface{}
+	if err = jsonAPI.Unmarshal(bs, &m); err != nil {
+		return
+	}
+
+	*p = Problem{}
+	for key, value := range m {
+		switch key {
+		case "type":
+			p.Type, _ = value.(string)
+		case "title":
+			p.Title, _ = value.(string)
+		case "status":
+			switch v := value.(type) {
+			case float64:
+				p.Status = int(v)
+			case json.Number:
+				// Numbers are decoded as json.Number when UseNumber is enabled
+				if i, err := v.Int64(); err == nil {
+					p.Status = int(i)
+				}
+			}
+		case "detail":
+			p.Detail, _ = value.(string)
+		case "instance":
+			p.Instance, _ = value.(string)
+		default:
+			p.Set(key, value)
+		}
+	}
+
+	return
+}
+
+// acceptsProblem will return whether or not the request accepts problem details
+func acceptsProblem(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	accept := r.Header.Get("Accept")
+	if len(accept) == 0 {
+		return false
+	}
+
+	return acceptRanges(parseAccept(accept)).explicit(problemContentType)
+}
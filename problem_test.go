@@ -0,0 +1,115 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bytedance/sonic"
+)
+
+func TestProblemMarshalJSON(t *testing.T) {
+	p := NewProblem(400, errors.New("name is required"))
+	p.Instance = "/users/1"
+	p.Set("balance", 30)
+
+	bs, err := p.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var np Problem
+	if err = sonic.Unmarshal(bs, &np); err != nil {
+		t.Fatal(err)
+	}
+
+	if np.Type != problemTypeBlank {
+		t.Fatalf("invalid type, expected \"%s\" and received \"%s\"", problemTypeBlank, np.Type)
+	}
+
+	if np.Title != "Bad Request" || np.Status != 400 || np.Detail != "name is required" || np.Instance != "/users/1" {
+		t.Fatalf("invalid problem, received %#v", np)
+	}
+
+	if np.Extensions["balance"] != float64(30) {
+		t.Fatalf("invalid extension value, expected %v and received %v", 30, np.Extensions["balance"])
+	}
+}
+
+func TestProblemUnmarshalJSON_useNumber(t *testing.T) {
+	defer SetJSONConfig(JSONConfig{})
+
+	for _, engine := range []JSONEngine{JSONEngineSonic, JSONEngineStandard} {
+		SetJSONConfig(JSONConfig{Engine: engine, UseNumber: true})
+
+		var p Problem
+		if err := jsonAPI.Unmarshal([]byte(`{"title":"Bad Request","status":400}`), &p); err != nil {
+			t.Fatal(err)
+		}
+
+		if p.Status != 400 {
+			t.Fatalf("invalid status for engine %v, expected %d and received %d", engine, 400, p.Status)
+		}
+	}
+}
+
+func TestContext_WriteJSON_problem(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "application/problem+json, application/json")
+	w := httptest.NewRecorder()
+	ctx := newContext(w, req, nil)
+	ctx.WriteJSON(422, errors.New("invalid email"))
+
+	if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+		t.Fatalf("invalid content type, expected \"%s\" and received \"%s\"", problemContentType, ct)
+	}
+
+	var p Problem
+	if err := sonic.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Status != 422 || p.Detail != "invalid email" {
+		t.Fatalf("invalid problem, received %#v", p)
+	}
+}
+
+func TestRouter_problemDetails(t *testing.T) {
+	r := newRouter()
+	r.SetProblemDetails(true)
+	r.SetPanic(func(v interface{}) {})
+	if err := r.GET("/users", newHandler([]Handler{func(ctx *Context) {}})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.POST("/panic", newHandler([]Handler{func(ctx *Context) { panic("oops") }})); err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		method string
+		url    string
+		status int
+		allow  string
+	}{
+		{method: "GET", url: "/missing", status: 404},
+		{method: "DELETE", url: "/users", status: 405, allow: "GET"},
+		{method: "POST", url: "/panic", status: 500},
+	}
+
+	for _, tc := range tcs {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.url, nil))
+		if w.Code != tc.status {
+			t.Fatalf("invalid status code for %s %s, expected %d and received %d", tc.method, tc.url, tc.status, w.Code)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+			t.Fatalf("invalid content type for %s %s, expected \"%s\" and received \"%s\"", tc.method, tc.url, problemContentType, ct)
+		}
+
+		if allow := w.Header().Get("Allow"); allow != tc.allow {
+			t.Fatalf("invalid Allow header for %s %s, expected \"%s\" and received \"%s\"", tc.method, tc.url, tc.allow, allow)
+		}
+	}
+}
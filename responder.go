@@ -0,0 +1,35 @@
+package httpserve
+
+import "io"
+
+var (
+	_ Responder = &TextResponse{}
+	_ Responder = &JSONResponse{}
+	_ Responder = &JSONPResponse{}
+	_ Responder = &HTMLResponse{}
+	_ Responder = &XMLResponse{}
+	_ Responder = &RedirectResponse{}
+	_ Responder = &NoContentResponse{}
+	_ Responder = &AdoptResponse{}
+)
+
+// Responder is a response value which can be written by a Context
+type Responder interface {
+	StatusCode() int
+	ContentType() string
+	WriteTo(w io.Writer) (n int64, err error)
+}
+
+// ResponderFunc is a handler which returns a Responder
+type ResponderFunc func(ctx *Context) Responder
+
+// NewResponderHandler will return a Handler which responds with the Responder returned by the provided func
+//
+// A nil Responder is not written, which allows a ResponderFunc to be used as middleware.
+func NewResponderHandler(fn ResponderFunc) Handler {
+	return func(ctx *Context) {
+		if resp := fn(ctx); resp != nil {
+			ctx.Respond(resp)
+		}
+	}
+}
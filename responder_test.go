@@ -0,0 +1,75 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewResponderHandler(t *testing.T) {
+	tcs := []struct {
+		name        string
+		method      string
+		fn          ResponderFunc
+		status      int
+		contentType string
+		body        string
+		location    string
+	}{
+		{
+			name:        "text",
+			method:      "GET",
+			fn:          func(ctx *Context) Responder { return NewTextResponse(200, []byte("pong")) },
+			status:      200,
+			contentType: "text/plain",
+			body:        "pong",
+		},
+		{
+			name:        "json error",
+			method:      "GET",
+			fn:          func(ctx *Context) Responder { return NewJSONResponse(400, errors.New("oops")) },
+			status:      400,
+			contentType: "application/json",
+			body:        "{\"errors\":[{\"message\":\"oops\"}]}\n",
+		},
+		{
+			name:     "redirect",
+			method:   "GET",
+			fn:       func(ctx *Context) Responder { return NewRedirectResponse(302, "/login") },
+			status:   302,
+			location: "/login",
+		},
+		{
+			name:   "no content",
+			method: "DELETE",
+			fn:     func(ctx *Context) Responder { return NewNoContentResponse() },
+			status: 204,
+		},
+	}
+
+	for _, tc := range tcs {
+		w := httptest.NewRecorder()
+		ctx := newContext(w, httptest.NewRequest(tc.method, "/", nil), nil)
+		NewResponderHandler(tc.fn)(ctx)
+
+		if w.Code != tc.status {
+			t.Fatalf("%s: invalid status code, expected %d and received %d", tc.name, tc.status, w.Code)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Fatalf("%s: invalid content type, expected \"%s\" and received \"%s\"", tc.name, tc.contentType, ct)
+		}
+
+		if body := w.Body.String(); body != tc.body {
+			t.Fatalf("%s: invalid body, expected %q and received %q", tc.name, tc.body, body)
+		}
+
+		if location := w.Header().Get("Location"); location != tc.location {
+			t.Fatalf("%s: invalid location, expected \"%s\" and received \"%s\"", tc.name, tc.location, location)
+		}
+
+		if !ctx.completed {
+			t.Fatalf("%s: expected context to be completed", tc.name)
+		}
+	}
+}
@@ -11,6 +11,7 @@ func newRoute(url string, h Handler, method string) (rp *route, err error) {
 		return
 	}
 
+	r.url = url
 	r.method = method
 	r.h = h
 	rp = &r
@@ -18,10 +19,22 @@ func newRoute(url string, h Handler, method string) (rp *route, err error) {
 }
 
 type route struct {
-	s []string
-	h Handler
+	s   []string
+	h   Handler
+	url string
 
 	method string
+	// consumes and produces are the media types declared for the route
+	consumes []string
+	produces []string
+}
+
+func (r *route) info() (info RouteInfo) {
+	info.Method = r.method
+	info.Path = r.url
+	info.Consumes = append([]string(nil), r.consumes...)
+	info.Produces = append([]string(nil), r.produces...)
+	return
 }
 
 func (r *route) numParams() (n int) {
@@ -14,7 +14,9 @@ const (
 func newRouter() *Router {
 	var r Router
 	r.SetNotFound(notFoundHandler)
+	r.SetMethodNotAllowed(methodNotAllowedHandler)
 	r.SetPanic(r.onPanic)
+	r.codecs = NewCodecs()
 	return &r
 }
 
@@ -23,11 +25,36 @@ type Router struct {
 	// rm is indexed by methodIndex for O(1) array access instead of map hashing.
 	rm [numMethods]routes
 
-	notFound Handler
-	panic    PanicHandler
+	notFound         Handler
+	methodNotAllowed Handler
+	panic            PanicHandler
 
 	errorFn func(error)
 
+	codecs *Codecs
+	// envelope is the envelope for JSON responses, DefaultEnvelope is used when unset
+	envelope Envelope
+	// pagination is the configuration for the Context pagination helpers
+	pagination PaginationConfig
+	// jsonPCallbackParam is the query parameter WriteJSONP reads the callback from
+	jsonPCallbackParam string
+	// multipart is the configuration for binding multipart/form-data bodies
+	multipart MultipartConfig
+
+	// maxBodySize is the default maximum request body size, zero is unlimited
+	maxBodySize int64
+	// bodyMemoryLimit is the size at which buffered request bodies are spilled to a temporary file
+	bodyMemoryLimit int64
+
+	// validateResponses determines whether or not JSON responses are validated against their response schema
+	validateResponses bool
+
+	// validateOnBind determines whether or not values are validated after being bound
+	validateOnBind bool
+
+	// problemDetails determines whether or not built-in error responses are written as RFC 9457 problem details
+	problemDetails bool
+
 	maxParams int
 }
 
@@ -68,22 +95,65 @@ func (r *Router) Match(method, url string) (h Handler, p Params, ok bool) {
 
 // match is the hot-path route matcher used by ServeHTTP. It fills the provided
 // Params slice in-place (from the pooled Context) instead of allocating a new
-// one, eliminating a per-request heap allocation.
-func (r *Router) match(method, url string, p *Params) Handler {
+// one, eliminating a per-request heap allocation. A nil route is returned when
+// no route matches.
+func (r *Router) match(method, url string, p *Params) *route {
 	idx := methodToIndex(method)
 	if idx == methodUnknown {
-		return r.notFound
+		return nil
 	}
 
 	var ok bool
 	for _, rt := range r.rm[idx] {
 		if *p, ok = rt.check(*p, url); ok {
-			return rt.h
+			return rt
 		}
 		*p = (*p)[:0]
 	}
 
-	return r.notFound
+	return nil
+}
+
+// allowed will return the set of methods which have a route matching the provided url
+//
+// The set is a bitmask so unmatched requests can be checked without allocating,
+// the Allow header value is only built when a 405 is responded with.
+func (r *Router) allowed(url string, p *Params) (methods methodSet) {
+	for idx, rs := range r.rm {
+		for _, rt := range rs {
+			var ok bool
+			*p, ok = rt.check(*p, url)
+			*p = (*p)[:0]
+			if ok {
+				methods.add(methodIndex(idx))
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// unmatched will return the Handler for a request which did not match any route
+func (r *Router) unmatched(rw http.ResponseWriter, req *http.Request, p *Params) Handler {
+	methods := r.allowed(req.URL.Path, p)
+	if methods == 0 {
+		return r.notFound
+	}
+
+	rw.Header().Set("Allow", methods.String())
+	return r.methodNotAllowed
+}
+
+// Routes will return the registered routes, including the media types they consume and produce
+func (r *Router) Routes() (rs []RouteInfo) {
+	for _, routes := range r.rm {
+		for _, rt := range routes {
+			rs = append(rs, rt.info())
+		}
+	}
+
+	return
 }
 
 // SetNotFound will set the not found handler (404)
@@ -91,6 +161,61 @@ func (r *Router) SetNotFound(hs ...Handler) {
 	r.notFound = newHandler(hs)
 }
 
+// SetMethodNotAllowed will set the method not allowed handler (405)
+func (r *Router) SetMethodNotAllowed(hs ...Handler) {
+	r.methodNotAllowed = newHandler(hs)
+}
+
+// SetProblemDetails will set whether or not the built-in 404, 405 and panic responses are written as problem details
+func (r *Router) SetProblemDetails(enabled bool) {
+	r.problemDetails = enabled
+}
+
+// SetCodec will set the codec for a media type, a nil codec will remove the media type
+func (r *Router) SetCodec(mediaType string, c Codec) {
+	r.codecs.Set(mediaType, c)
+}
+
+// SetMaxBodySize will set the maximum request body size, zero is unlimited
+func (r *Router) SetMaxBodySize(n int64) {
+	r.maxBodySize = n
+}
+
+// SetBodyMemoryLimit will set the size at which request bodies buffered by Context.Body are spilled to a temporary file
+func (r *Router) SetBodyMemoryLimit(n int64) {
+	r.bodyMemoryLimit = n
+}
+
+// SetValidateResponses will set whether or not JSON responses are validated against the schema set by ResponseSchema
+func (r *Router) SetValidateResponses(enabled bool) {
+	r.validateResponses = enabled
+}
+
+// SetValidateOnBind will set whether or not values are validated after being bound by the Context bind helpers
+func (r *Router) SetValidateOnBind(enabled bool) {
+	r.validateOnBind = enabled
+}
+
+// SetEnvelope will set the envelope for JSON responses
+func (r *Router) SetEnvelope(e Envelope) {
+	r.envelope = e
+}
+
+// SetPagination will set the configuration for the Context pagination helpers
+func (r *Router) SetPagination(cfg PaginationConfig) {
+	r.pagination = cfg
+}
+
+// SetJSONPCallbackParam will set the query parameter Context.WriteJSONP reads the callback from
+func (r *Router) SetJSONPCallbackParam(param string) {
+	r.jsonPCallbackParam = param
+}
+
+// SetMultipart will set the configuration for binding multipart/form-data bodies
+func (r *Router) SetMultipart(cfg MultipartConfig) {
+	r.multipart = cfg
+}
+
 // SetPanic will set panic handler
 func (r *Router) SetPanic(h PanicHandler) {
 	r.panic = h
@@ -102,6 +227,11 @@ func (r *Router) SetOnError(onError func(error)) {
 
 // Handle will create a route for any method
 func (r *Router) Handle(method, url string, h Handler) (err error) {
+	return r.handle(method, url, h, nil, nil)
+}
+
+// handle will create a route for any method, with the media types it consumes and produces
+func (r *Router) handle(method, url string, h Handler, consumes, produces []string) (err error) {
 	idx := methodToIndex(method)
 	if idx == methodUnknown {
 		return fmt.Errorf("unsupported HTTP method: %s", method)
@@ -112,6 +242,8 @@ func (r *Router) Handle(method, url string, h Handler) (err error) {
 		return fmt.Errorf("error creating route for [%s] \"%s\": %v", method, url, err)
 	}
 
+	rt.consumes = consumes
+	rt.produces = produces
 	if n := rt.numParams(); n > r.maxParams {
 		r.maxParams = n
 	}
@@ -145,11 +277,23 @@ func (r *Router) OPTIONS(url string, h Handler) error {
 	return r.Handle("OPTIONS", url, h)
 }
 
+// PATCH will create a PATCH route
+func (r *Router) PATCH(url string, h Handler) error {
+	return r.Handle("PATCH", url, h)
+}
+
 func (r *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	ctx := acquireContext(rw, req)
 	ctx.errorFn = r.onError
-
-	h := r.match(req.Method, req.URL.Path, &ctx.Params)
+	ctx.router = r
+	ctx.maxBodySize = r.maxBodySize
+
+	var h Handler
+	if ctx.route = r.match(req.Method, req.URL.Path, &ctx.Params); ctx.route != nil {
+		h = ctx.route.h
+	} else {
+		h = r.unmatched(rw, req, &ctx.Params)
+	}
 
 	// panicked starts true; set to false on clean exit so the deferred
 	// recovery only fires when an actual panic occurred.
@@ -160,11 +304,24 @@ func (r *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 			if r.panic != nil {
 				r.panic(v)
 			}
-			rw.WriteHeader(500)
+
+			r.writePanic(ctx)
 		}
 		releaseContext(ctx)
 	}()
 
-	h(ctx)
+	if ctx.route == nil || ctx.checkMediaTypes() {
+		h(ctx)
+	}
+
 	panicked = false
 }
+
+func (r *Router) writePanic(ctx *Context) {
+	if ctx.completed || !ctx.prefersProblem() {
+		ctx.writer.WriteHeader(500)
+		return
+	}
+
+	ctx.WriteProblem(NewProblem(500, nil))
+}
@@ -205,3 +205,25 @@ func BenchmarkRouter_large(b *testing.B) {
 
 	b.ReportAllocs()
 }
+
+func TestRouter_allowed(t *testing.T) {
+	r := newRouter()
+	r.GET("/users", func(ctx *Context) {})
+	r.POST("/users", func(ctx *Context) {})
+	r.DELETE("/users/:id", func(ctx *Context) {})
+
+	p := make(Params, 0, r.maxParams)
+	if allow := r.allowed("/users", &p).String(); allow != "GET, POST" {
+		t.Fatalf("invalid allowed methods, expected %q and received %q", "GET, POST", allow)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if r.allowed("/missing", &p) != 0 {
+			t.Fatal("expected no allowed methods")
+		}
+	})
+
+	if allocs > 0 {
+		t.Fatalf("expected unmatched requests not to allocate, received %v allocations", allocs)
+	}
+}
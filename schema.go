@@ -0,0 +1,215 @@
+package httpserve
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var schemaPrinter = message.NewPrinter(language.English)
+
+// ErrInvalidJSON is returned when a document validated against a schema is not valid JSON
+var ErrInvalidJSON error = newStatusError(400, "invalid JSON")
+
+// LoadSchema will load and compile a JSON Schema from a local file
+//
+// Schemas default to draft 2020-12 when $schema is not declared, relative $ref
+// values are resolved against the file location.
+func LoadSchema(filename string) (s *Schema, err error) {
+	var abs string
+	if abs, err = filepath.Abs(filename); err != nil {
+		return
+	}
+
+	return compileSchema(jsonschema.FileLoader{}, (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String())
+}
+
+// LoadSchemaFS will load and compile a JSON Schema from a file system, such as an embed.FS
+//
+// Schemas default to draft 2020-12 when $schema is not declared, relative $ref
+// values are resolved within the file system.
+func LoadSchemaFS(fsys fs.FS, filename string) (s *Schema, err error) {
+	return compileSchema(&fsSchemaLoader{fsys: fsys}, (&url.URL{Scheme: "file", Path: "/" + filename}).String())
+}
+
+func compileSchema(loader jsonschema.URLLoader, location string) (s *Schema, err error) {
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.UseLoader(jsonschema.SchemeURLLoader{"file": loader})
+
+	var sch *jsonschema.Schema
+	if sch, err = c.Compile(location); err != nil {
+		return
+	}
+
+	return &Schema{s: sch}, nil
+}
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	s *jsonschema.Schema
+}
+
+// Validate will validate a JSON document against the schema
+//
+// Violations are returned as FieldErrors, with the field set to the JSON
+// pointer of the invalid location and the code set to the failed keyword.
+// Documents which are not valid JSON return an error wrapping ErrInvalidJSON.
+func (s *Schema) Validate(doc []byte) (err error) {
+	var value interface{}
+	if value, err = jsonschema.UnmarshalJSON(bytes.NewReader(doc)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	return s.validate(value)
+}
+
+// ValidateReader will validate a JSON document read from a reader against the schema, see Validate
+func (s *Schema) ValidateReader(r io.Reader) (err error) {
+	var value interface{}
+	if value, err = jsonschema.UnmarshalJSON(r); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	return s.validate(value)
+}
+
+func (s *Schema) validate(value interface{}) (err error) {
+	if err = s.s.Validate(value); err == nil {
+		return
+	}
+
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return
+	}
+
+	var errs FieldErrors
+	appendSchemaErrors(&errs, verr)
+	return errs.err()
+}
+
+// RequestSchema will return a Handler which validates the request body against the provided schema
+//
+// The body is buffered using Context.Body, so subsequent handlers can still
+// bind it. Invalid bodies are responded to with a 422 listing the JSON
+// pointer locations of each violation, bodies which are not valid JSON are
+// responded to with a 400.
+//
+// Note: This must precede any handlers which read the request body.
+func RequestSchema(s *Schema) Handler {
+	return func(ctx *Context) {
+		body, err := ctx.Body()
+		if err == nil {
+			err = s.ValidateReader(body)
+		}
+
+		if err == nil {
+			return
+		}
+
+		statusCode := ErrorStatusCode(err, 400)
+		var ferrs FieldErrors
+		if errors.As(err, &ferrs) {
+			// Only schema violations are unprocessable, anything else is a bad request
+			statusCode = 422
+		}
+
+		ctx.WriteJSON(statusCode, err)
+	}
+}
+
+// ResponseSchema will return a Handler which sets the schema JSON responses are validated against
+//
+// Responses are only validated when response validation is enabled for the
+// server (see Serve.SetValidateResponses). Violations are reported to the
+// server's error handler, the response is written regardless.
+func ResponseSchema(s *Schema) Handler {
+	return func(ctx *Context) {
+		ctx.responseSchema = s
+	}
+}
+
+// validateResponse will validate an encoded JSON response against the response schema when enabled
+func (c *Context) validateResponse(bs []byte) {
+	if c.responseSchema == nil || c.router == nil || !c.router.validateResponses {
+		return
+	}
+
+	if err := c.responseSchema.Validate(bs); err != nil {
+		c.errorFn(fmt.Errorf("httpserve: response for %s %s does not match schema: %w", c.request.Method, c.request.URL.Path, err))
+	}
+}
+
+func appendSchemaErrors(errs *FieldErrors, verr *jsonschema.ValidationError) {
+	if len(verr.Causes) > 0 {
+		for _, cause := range verr.Causes {
+			appendSchemaErrors(errs, cause)
+		}
+
+		return
+	}
+
+	location := verr.InstanceLocation
+	keyword := strings.Join(verr.ErrorKind.KeywordPath(), "/")
+	if req, ok := verr.ErrorKind.(*kind.Required); ok {
+		// Point to each missing property rather than the parent object
+		for _, missing := range req.Missing {
+			*errs = append(*errs, newSchemaError(append(location[:len(location):len(location)], missing), keyword, "is required"))
+		}
+
+		return
+	}
+
+	*errs = append(*errs, newSchemaError(location, keyword, verr.ErrorKind.LocalizedString(schemaPrinter)))
+}
+
+func newSchemaError(location []string, keyword, msg string) *FieldError {
+	var f FieldError
+	f.Source = "body"
+	f.Field = jsonPointer(location)
+	f.Code = keyword
+	f.Err = &validationError{msg: msg}
+	return &f
+}
+
+// jsonPointer will return the RFC 6901 JSON Pointer for the provided reference tokens
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+
+	return sb.String()
+}
+
+// fsSchemaLoader loads schemas referenced by file URLs from a file system
+type fsSchemaLoader struct {
+	fsys fs.FS
+}
+
+func (f *fsSchemaLoader) Load(location string) (value interface{}, err error) {
+	var u *url.URL
+	if u, err = url.Parse(location); err != nil {
+		return
+	}
+
+	var file fs.File
+	if file, err = f.fsys.Open(strings.TrimPrefix(u.Path, "/")); err != nil {
+		return
+	}
+	defer file.Close()
+
+	return jsonschema.UnmarshalJSON(file)
+}
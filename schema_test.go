@@ -0,0 +1,104 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testSchemaFS = fstest.MapFS{
+	"user.json": {Data: []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 3},
+			"age": {"type": "integer", "minimum": 0},
+			"address": {"$ref": "address.json"}
+		}
+	}`)},
+	"address.json": {Data: []byte(`{"type": "object", "required": ["city"]}`)},
+	"response.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["data"],
+		"properties": {"data": {"$ref": "user.json"}}
+	}`)},
+}
+
+func TestRequestSchema(t *testing.T) {
+	s, err := LoadSchemaFS(testSchemaFS, "user.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ts TestJSONStruct
+	r := newRouter()
+	if err = r.POST("/users", newHandler([]Handler{RequestSchema(s), func(ctx *Context) {
+		if err := ctx.Bind(&ts); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx.WriteNoContent()
+	}})); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users", strings.NewReader(`{"age":-1,"address":{}}`)))
+	if w.Code != 422 {
+		t.Fatalf("invalid status code, expected %d and received %d", 422, w.Code)
+	}
+
+	for _, location := range []string{`"field":"/name"`, `"field":"/age"`, `"field":"/address/city"`} {
+		if !strings.Contains(w.Body.String(), location) {
+			t.Fatalf("expected body to contain %s, received %s", location, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"John Doe","age":33}`)))
+	if w.Code != 204 {
+		t.Fatalf("invalid status code, expected %d and received %d", 204, w.Code)
+	}
+
+	if ts.Name != "John Doe" {
+		t.Fatalf("invalid name, expected \"%s\" and received \"%s\"", "John Doe", ts.Name)
+	}
+
+	for _, body := range []string{"", `{"name":`} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/users", strings.NewReader(body)))
+		if w.Code != 400 {
+			t.Fatalf("invalid status code for %q, expected %d and received %d", body, 400, w.Code)
+		}
+	}
+}
+
+func TestResponseSchema(t *testing.T) {
+	s, err := LoadSchemaFS(testSchemaFS, "response.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []error
+	r := newRouter()
+	r.SetValidateResponses(true)
+	r.SetOnError(func(err error) { errs = append(errs, err) })
+	if err = r.GET("/user", newHandler([]Handler{ResponseSchema(s), func(ctx *Context) {
+		ctx.WriteJSON(200, TestJSONStruct{Name: "Jo"})
+	}})); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/user", nil))
+	if w.Code != 200 {
+		t.Fatalf("invalid status code, expected %d and received %d", 200, w.Code)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], ErrValidation) {
+		t.Fatalf("expected a single validation error, received %v", errs)
+	}
+}
@@ -0,0 +1,43 @@
+package httpserve
+
+import "errors"
+
+// StatusCoder is an optional interface for errors which are associated with an HTTP status code
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// ErrorStatusCode will return the HTTP status code associated with an error, the fallback is returned when none is found
+func ErrorStatusCode(err error, fallback int) (statusCode int) {
+	var sc StatusCoder
+	if !errors.As(err, &sc) {
+		return fallback
+	}
+
+	if statusCode = sc.StatusCode(); statusCode == 0 {
+		return fallback
+	}
+
+	return
+}
+
+func newStatusError(statusCode int, message string) *statusError {
+	var s statusError
+	s.statusCode = statusCode
+	s.message = message
+	return &s
+}
+
+// statusError is an error associated with an HTTP status code
+type statusError struct {
+	statusCode int
+	message    string
+}
+
+func (s *statusError) Error() string {
+	return s.message
+}
+
+func (s *statusError) StatusCode() int {
+	return s.statusCode
+}
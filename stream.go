@@ -0,0 +1,202 @@
+package httpserve
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"iter"
+	"mime"
+	"net/http"
+	"time"
+)
+
+const (
+	csvContentType    = "text/csv; charset=utf-8"
+	ndjsonContentType = "application/x-ndjson"
+
+	// streamFlushInterval is the maximum duration streamed values are buffered before being flushed to the client
+	streamFlushInterval = 100 * time.Millisecond
+)
+
+// SetAttachment will set the Content-Disposition header, so the response is downloaded as a file with the provided name
+func (c *Context) SetAttachment(filename string) {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	if len(disposition) == 0 {
+		// Filename cannot be represented, fallback to an attachment without a name
+		disposition = "attachment"
+	}
+
+	c.writer.Header().Set("Content-Disposition", disposition)
+}
+
+// WriteCSV will stream a CSV response, writing the header (when not nil) followed by each row of the sequence
+//
+// Rows are flushed to the client periodically and streaming stops when the
+// client disconnects. Errors yielded by the sequence, or returned while
+// writing, are written as an error response when nothing has been sent yet.
+// Once the response is committed, errors are reported to the server's error
+// handler and the response is ended without modifying the committed status.
+func (c *Context) WriteCSV(statusCode int, header []string, rows iter.Seq2[[]string, error]) {
+	if c.completed {
+		c.errorFn(ErrContextIsClosed)
+		return
+	}
+	defer c.close()
+
+	if redirected := c.tryRedirect(statusCode); redirected {
+		// Request was redirected, return
+		return
+	}
+
+	sw := newStreamWriter(c, statusCode, csvContentType)
+	cw := csv.NewWriter(sw)
+	write := func(row []string) error {
+		return cw.Write(row)
+	}
+
+	flush := func() (err error) {
+		if cw.Flush(); cw.Error() != nil {
+			return cw.Error()
+		}
+
+		return sw.flush()
+	}
+
+	if header != nil {
+		if err := write(header); err != nil {
+			sw.fail(err)
+			return
+		}
+	}
+
+	writeStream(c, sw, rows, write, flush)
+}
+
+// WriteNDJSON will stream a newline delimited JSON response, writing each value of the sequence on its own line
+//
+// Values are flushed to the client periodically and streaming stops when the
+// client disconnects. Errors are handled as described by WriteCSV.
+func (c *Context) WriteNDJSON(statusCode int, values iter.Seq2[interface{}, error]) {
+	if c.completed {
+		c.errorFn(ErrContextIsClosed)
+		return
+	}
+	defer c.close()
+
+	if redirected := c.tryRedirect(statusCode); redirected {
+		// Request was redirected, return
+		return
+	}
+
+	sw := newStreamWriter(c, statusCode, ndjsonContentType)
+	bw := bufio.NewWriter(sw)
+	write := func(value interface{}) error {
+		return jsonAPI.Encode(bw, value)
+	}
+
+	flush := func() (err error) {
+		if err = bw.Flush(); err != nil {
+			return
+		}
+
+		return sw.flush()
+	}
+
+	writeStream(c, sw, values, write, flush)
+}
+
+// writeStream will write each value of the sequence, flushing periodically until the sequence ends, an error occurs or the client disconnects
+func writeStream[T any](c *Context, sw *streamWriter, values iter.Seq2[T, error], write func(T) error, flush func() error) {
+	done := c.request.Context().Done()
+	for value, err := range values {
+		select {
+		case <-done:
+			// Client has disconnected, stop streaming
+			return
+		default:
+		}
+
+		if err == nil {
+			err = write(value)
+		}
+
+		if err != nil {
+			sw.fail(err)
+			return
+		}
+
+		if time.Since(sw.lastFlush) < streamFlushInterval {
+			continue
+		}
+
+		if err = flush(); err != nil {
+			c.errorFn(err)
+			return
+		}
+	}
+
+	if err := flush(); err != nil {
+		c.errorFn(err)
+	}
+}
+
+func newStreamWriter(c *Context, statusCode int, contentType string) *streamWriter {
+	var s streamWriter
+	s.c = c
+	s.statusCode = statusCode
+	s.contentType = contentType
+	s.lastFlush = time.Now()
+	return &s
+}
+
+// streamWriter writes a streamed response, committing the status code on the first write
+type streamWriter struct {
+	c           *Context
+	statusCode  int
+	contentType string
+
+	committed bool
+	lastFlush time.Time
+}
+
+func (s *streamWriter) Write(bs []byte) (n int, err error) {
+	s.commit()
+	return s.c.writer.Write(bs)
+}
+
+func (s *streamWriter) commit() {
+	if s.committed {
+		return
+	}
+
+	s.committed = true
+	s.c.setContentType(s.contentType)
+	s.c.setStatusCode(s.statusCode)
+}
+
+// flush will flush the written response to the client
+func (s *streamWriter) flush() (err error) {
+	s.commit()
+	s.lastFlush = time.Now()
+	if err = http.NewResponseController(s.c.writer).Flush(); errors.Is(err, http.ErrNotSupported) {
+		// Writer does not support flushing, the response is sent when the handler returns
+		return nil
+	}
+
+	return
+}
+
+// fail will handle an error encountered while streaming
+//
+// Until the response is committed the error is written as an error response,
+// any buffered values are discarded. Afterwards, the status code has already
+// been sent so the error is reported to the server's error handler instead.
+func (s *streamWriter) fail(err error) {
+	if s.committed {
+		s.c.errorFn(err)
+		return
+	}
+
+	s.c.writer.Header().Del("Content-Disposition")
+	s.c.writeJSON(ErrorStatusCode(err, 500), err, nil)
+}
@@ -0,0 +1,146 @@
+package httpserve
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_WriteCSV(t *testing.T) {
+	rows := func(yield func([]string, error) bool) {
+		for _, row := range [][]string{{"1", "John Doe"}, {"2", "Jane, Doe"}} {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/", nil), nil)
+	ctx.SetAttachment("users.csv")
+	ctx.WriteCSV(200, []string{"id", "name"}, rows)
+
+	if w.Code != 200 {
+		t.Fatalf("invalid status code, expected %d and received %d", 200, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != csvContentType {
+		t.Fatalf("invalid content type, expected %q and received %q", csvContentType, ct)
+	}
+
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename=users.csv` {
+		t.Fatalf("invalid content disposition, received %q", cd)
+	}
+
+	expected := "id,name\n1,John Doe\n2,\"Jane, Doe\"\n"
+	if body := w.Body.String(); body != expected {
+		t.Fatalf("invalid body, expected %q and received %q", expected, body)
+	}
+}
+
+func TestContext_WriteNDJSON(t *testing.T) {
+	values := func(yield func(interface{}, error) bool) {
+		for i, name := range []string{"a", "b"} {
+			if !yield(TestJSONStruct{Name: name, Age: i}, nil) {
+				return
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	newContext(w, httptest.NewRequest("GET", "/", nil), nil).WriteNDJSON(200, values)
+
+	if ct := w.Header().Get("Content-Type"); ct != ndjsonContentType {
+		t.Fatalf("invalid content type, expected %q and received %q", ndjsonContentType, ct)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("invalid number of lines, expected %d and received %d", 2, len(lines))
+	}
+
+	for i, line := range lines {
+		var ts TestJSONStruct
+		if err := jsonAPI.Unmarshal([]byte(line), &ts); err != nil {
+			t.Fatal(err)
+		}
+
+		if ts.Name != []string{"a", "b"}[i] {
+			t.Fatalf("invalid name for line %d, received %q", i, ts.Name)
+		}
+	}
+}
+
+func TestContext_WriteNDJSON_errors(t *testing.T) {
+	errDatabase := newStatusError(503, "database unavailable")
+	failAfter := func(n int) iter.Seq2[interface{}, error] {
+		return func(yield func(interface{}, error) bool) {
+			for i := 0; i < n; i++ {
+				if !yield(TestJSONStruct{Name: strings.Repeat("a", 1024)}, nil) {
+					return
+				}
+			}
+
+			yield(nil, errDatabase)
+		}
+	}
+
+	// Errors before the response is committed are written as an error response
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/", nil), nil)
+	ctx.SetAttachment("export.ndjson")
+	ctx.WriteNDJSON(200, failAfter(1))
+	if w.Code != 503 {
+		t.Fatalf("invalid status code, expected %d and received %d", 503, w.Code)
+	}
+
+	if cd := w.Header().Get("Content-Disposition"); len(cd) > 0 {
+		t.Fatalf("expected content disposition to be removed, received %q", cd)
+	}
+
+	// Errors after the response is committed are reported without modifying the response
+	var reported error
+	w = httptest.NewRecorder()
+	ctx = newContext(w, httptest.NewRequest("GET", "/", nil), nil)
+	ctx.errorFn = func(err error) { reported = err }
+	ctx.WriteNDJSON(200, failAfter(8))
+	if w.Code != 200 {
+		t.Fatalf("invalid status code, expected %d and received %d", 200, w.Code)
+	}
+
+	if !errors.Is(reported, errDatabase) {
+		t.Fatalf("invalid reported error, expected %v and received %v", errDatabase, reported)
+	}
+
+	if strings.Contains(w.Body.String(), "database unavailable") {
+		t.Fatal("expected error not to be written to the committed response")
+	}
+}
+
+func TestContext_WriteCSV_disconnect(t *testing.T) {
+	reqCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var n int
+	rows := func(yield func([]string, error) bool) {
+		for n = 0; n < 100; n++ {
+			if n == 2 {
+				// Simulate the client disconnecting mid-stream
+				cancel()
+			}
+
+			if !yield([]string{"row"}, nil) {
+				return
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	newContext(w, httptest.NewRequest("GET", "/", nil).WithContext(reqCtx), nil).WriteCSV(200, nil, rows)
+	if n != 2 {
+		t.Fatalf("expected streaming to stop after %d rows, stopped after %d", 2, n)
+	}
+}
@@ -98,9 +98,23 @@ func shiftStr(str string, n int) string {
 }
 
 func notFoundHandler(ctx *Context) {
+	if ctx.prefersProblem() {
+		ctx.WriteProblem(NewProblem(404, nil))
+		return
+	}
+
 	ctx.WriteString(404, "text/plain", "404, not found")
 }
 
+func methodNotAllowedHandler(ctx *Context) {
+	if ctx.prefersProblem() {
+		ctx.WriteProblem(NewProblem(405, nil))
+		return
+	}
+
+	ctx.WriteString(405, "text/plain", "405, method not allowed")
+}
+
 // PanicHandler is a panic handler
 type PanicHandler func(v interface{})
 
@@ -0,0 +1,370 @@
+package httpserve
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	// ErrValidation is matched (using errors.Is) by all validation rule failures
+	ErrValidation error = newStatusError(422, "validation failed")
+
+	uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+var (
+	validatorsMux sync.RWMutex
+	validators    = map[string]ValidatorFunc{
+		"min":   validateMin,
+		"max":   validateMax,
+		"len":   validateLen,
+		"email": validateEmail,
+		"oneof": validateOneOf,
+		"uuid":  validateUUID,
+	}
+
+	// validationCache caches the parsed validation rules of struct types
+	validationCache sync.Map
+)
+
+// ValidatorFunc validates a field value using the rule parameter (the value following "=" within the tag)
+//
+// The returned error message is used as the field error message.
+type ValidatorFunc func(field reflect.Value, param string) error
+
+// RegisterValidator will register a custom validation rule to be referenced within validate struct tags
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorsMux.Lock()
+	defer validatorsMux.Unlock()
+	validators[name] = fn
+}
+
+// Validate will validate a struct (or pointer to a struct) using the validate struct tags
+//
+// Supported rules are required, min, max, len, email, oneof, uuid and any
+// registered custom rules. Rules other than required are skipped for zero
+// values. Nested structs, pointers to structs and slices of structs are
+// validated recursively. Rule failures are returned as FieldErrors which
+// match ErrValidation.
+func Validate(value interface{}) (err error) {
+	rval := reflect.ValueOf(value)
+	for rval.Kind() == reflect.Ptr {
+		if rval.IsNil() {
+			return
+		}
+
+		rval = rval.Elem()
+	}
+
+	if rval.Kind() != reflect.Struct {
+		return
+	}
+
+	var errs FieldErrors
+	if err = validateStruct(rval, "", &errs); err != nil {
+		return
+	}
+
+	return errs.err()
+}
+
+// Validate will validate a value using the validate struct tags, see Validate
+func (c *Context) Validate(value interface{}) (err error) {
+	return Validate(value)
+}
+
+// BindAndValidate will bind the request body to the provided value using Bind and validate the result
+func (c *Context) BindAndValidate(value interface{}) (err error) {
+	if err = c.bind(value); err != nil {
+		return
+	}
+
+	return Validate(value)
+}
+
+// autoValidate will validate a bound value when validation on bind is enabled for the Router
+func (c *Context) autoValidate(value interface{}) (err error) {
+	if c.router == nil || !c.router.validateOnBind {
+		return
+	}
+
+	return Validate(value)
+}
+
+func validateStruct(rval reflect.Value, prefix string, errs *FieldErrors) (err error) {
+	var fields []validationField
+	if fields, err = getValidationFields(rval.Type()); err != nil {
+		return
+	}
+
+	for _, f := range fields {
+		name := prefix + f.name
+		field := rval.Field(f.index)
+		for _, r := range f.rules {
+			if r.name != "required" && field.IsZero() {
+				continue
+			}
+
+			var rerr error
+			if rerr = r.validate(field); rerr == nil {
+				continue
+			}
+
+			*errs = append(*errs, &FieldError{Field: name, Code: r.name, Err: &validationError{msg: rerr.Error()}})
+			// Only report the first failed rule for a field
+			break
+		}
+
+		if err = validateNested(field, name, errs); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func validateNested(field reflect.Value, name string, errs *FieldErrors) (err error) {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return
+		}
+
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Struct:
+		return validateStruct(field, name+".", errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len(); i++ {
+			if err = validateNested(field.Index(i), name+"["+strconv.Itoa(i)+"]", errs); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// getValidationFields will return the validated fields of a struct type, including fields which may contain nested structs
+func getValidationFields(rtype reflect.Type) (fields []validationField, err error) {
+	if cached, ok := validationCache.Load(rtype); ok {
+		return cached.([]validationField), nil
+	}
+
+	for i := 0; i < rtype.NumField(); i++ {
+		sf := rtype.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag := sf.Tag.Get("validate")
+		if tag == "-" || (len(tag) == 0 && !mayContainStruct(sf.Type)) {
+			continue
+		}
+
+		f := validationField{index: i, name: fieldName(sf)}
+		if f.rules, err = parseRules(tag); err != nil {
+			err = fmt.Errorf("invalid validate tag for %s.%s: %v", rtype.Name(), sf.Name, err)
+			return
+		}
+
+		fields = append(fields, f)
+	}
+
+	validationCache.Store(rtype, fields)
+	return
+}
+
+func parseRules(tag string) (rules []validationRule, err error) {
+	if len(tag) == 0 {
+		return
+	}
+
+	validatorsMux.RLock()
+	defer validatorsMux.RUnlock()
+	for _, part := range strings.Split(tag, ",") {
+		var r validationRule
+		r.name, r.param, _ = strings.Cut(strings.TrimSpace(part), "=")
+		if r.name == "required" {
+			rules = append(rules, r)
+			continue
+		}
+
+		var ok bool
+		if r.fn, ok = validators[r.name]; !ok {
+			return nil, fmt.Errorf("unknown validation rule \"%s\"", r.name)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return
+}
+
+// fieldName will return the name of a field as referenced by a request, using the json or form tag when available
+func fieldName(sf reflect.StructField) string {
+	for _, key := range [...]string{"json", "form", "query", "param", "header", "cookie"} {
+		name, _, _ := strings.Cut(sf.Tag.Get(key), ",")
+		if len(name) > 0 && name != "-" {
+			return name
+		}
+	}
+
+	return sf.Name
+}
+
+func mayContainStruct(rtype reflect.Type) bool {
+	for {
+		switch rtype.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			rtype = rtype.Elem()
+		case reflect.Struct, reflect.Interface:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+type validationField struct {
+	index int
+	name  string
+	rules []validationRule
+}
+
+type validationRule struct {
+	name  string
+	param string
+	fn    ValidatorFunc
+}
+
+func (r *validationRule) validate(field reflect.Value) error {
+	if r.name == "required" {
+		if field.IsZero() || (hasLen(field) && field.Len() == 0) {
+			return errors.New("is required")
+		}
+
+		return nil
+	}
+
+	for field.Kind() == reflect.Ptr {
+		field = field.Elem()
+	}
+
+	return r.fn(field, r.param)
+}
+
+// validationError is the error of a failed validation rule
+type validationError struct {
+	msg string
+}
+
+func (v *validationError) Error() string {
+	return v.msg
+}
+
+func (v *validationError) StatusCode() int {
+	return 422
+}
+
+func (v *validationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func hasLen(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// size will return the length of a string (in runes) or collection, or the numeric value of a number
+func size(field reflect.Value) (n float64, err error) {
+	switch field.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(field.String())), nil
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(field.Len()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), nil
+	default:
+		return 0, fmt.Errorf("cannot determine size of %s", field.Kind())
+	}
+}
+
+func compareSize(field reflect.Value, param string, cmp func(n, limit float64) bool, msg string) (err error) {
+	var limit, n float64
+	if limit, err = strconv.ParseFloat(param, 64); err != nil {
+		return fmt.Errorf("invalid parameter \"%s\"", param)
+	}
+
+	if n, err = size(field); err != nil {
+		return
+	}
+
+	if cmp(n, limit) {
+		return
+	}
+
+	if hasLen(field) {
+		return fmt.Errorf("must have a length of %s %s", msg, param)
+	}
+
+	return fmt.Errorf("must be %s %s", msg, param)
+}
+
+func validateMin(field reflect.Value, param string) error {
+	return compareSize(field, param, func(n, limit float64) bool { return n >= limit }, "at least")
+}
+
+func validateMax(field reflect.Value, param string) error {
+	return compareSize(field, param, func(n, limit float64) bool { return n <= limit }, "at most")
+}
+
+func validateLen(field reflect.Value, param string) error {
+	return compareSize(field, param, func(n, limit float64) bool { return n == limit }, "exactly")
+}
+
+func validateEmail(field reflect.Value, param string) error {
+	str := fmt.Sprint(field.Interface())
+	addr, err := mail.ParseAddress(str)
+	if err != nil || addr.Address != str {
+		return errors.New("must be a valid email address")
+	}
+
+	return nil
+}
+
+func validateOneOf(field reflect.Value, param string) error {
+	str := fmt.Sprint(field.Interface())
+	options := strings.Fields(param)
+	for _, option := range options {
+		if str == option {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("must be one of [%s]", strings.Join(options, ", "))
+}
+
+func validateUUID(field reflect.Value, param string) error {
+	if !uuidRegexp.MatchString(fmt.Sprint(field.Interface())) {
+		return errors.New("must be a valid UUID")
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package httpserve
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	RegisterValidator("even", func(field reflect.Value, param string) error {
+		if field.Int()%2 != 0 {
+			return errors.New("must be even")
+		}
+
+		return nil
+	})
+
+	type testItem struct {
+		SKU      string `json:"sku" validate:"required,len=4"`
+		Quantity int    `json:"quantity" validate:"min=1,even"`
+	}
+
+	type testAddress struct {
+		City string `json:"city" validate:"required"`
+	}
+
+	type testRequest struct {
+		Name    string       `json:"name" validate:"required,min=3,max=8"`
+		Email   string       `json:"email" validate:"email"`
+		Role    string       `json:"role" validate:"oneof=admin user"`
+		ID      string       `json:"id" validate:"uuid"`
+		Address *testAddress `json:"address"`
+		Items   []testItem   `json:"items" validate:"required"`
+	}
+
+	tr := testRequest{
+		Name:    "Jo",
+		Email:   "not-an-email",
+		Role:    "owner",
+		ID:      "6f1f8a7e-7b8c-4c2e-9d1a-0b2c3d4e5f60",
+		Address: &testAddress{},
+		Items:   []testItem{{SKU: "ABCD", Quantity: 2}, {SKU: "ABC", Quantity: 3}},
+	}
+
+	err := Validate(&tr)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error and received %v", err)
+	}
+
+	if code := ErrorStatusCode(err, 400); code != 422 {
+		t.Fatalf("invalid status code, expected %d and received %d", 422, code)
+	}
+
+	expected := "name: must have a length of at least 3; email: must be a valid email address; role: must be one of [admin, user]; " +
+		"address.city: is required; items[1].sku: must have a length of exactly 4; items[1].quantity: must be even"
+	if err.Error() != expected {
+		t.Fatalf("invalid error, expected \"%s\" and received \"%s\"", expected, err.Error())
+	}
+}
+
+func TestContext_BindAndValidate(t *testing.T) {
+	type testRequest struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ctx := newContext(w, req, nil)
+
+	var tr testRequest
+	err := ctx.BindAndValidate(&tr)
+	ctx.WriteJSON(ErrorStatusCode(err, 400), err)
+
+	if w.Code != 422 {
+		t.Fatalf("invalid status code, expected %d and received %d", 422, w.Code)
+	}
+
+	expected := "{\"errors\":[{\"message\":\"name: is required\",\"code\":\"required\",\"field\":\"name\"}]}\n"
+	if body := w.Body.String(); body != expected {
+		t.Fatalf("invalid body, expected %q and received %q", expected, body)
+	}
+}
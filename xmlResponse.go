@@ -1,6 +1,7 @@
 package httpserve
 
 import (
+	"encoding/xml"
 	"io"
 )
 
@@ -12,10 +13,20 @@ func NewXMLResponse(code int, value []byte) *XMLResponse {
 	return &j
 }
 
+// NewXMLValueResponse will return a new XML response which marshals the provided value using encoding/xml
+func NewXMLValueResponse(code int, value interface{}) *XMLResponse {
+	var j XMLResponse
+	j.code = code
+	j.v = value
+	return &j
+}
+
 // XMLResponse is a basic text response
 type XMLResponse struct {
 	code int
 	val  []byte
+	// v is the value to be marshaled, used when val is not set
+	v interface{}
 }
 
 // ContentType returns the content type
@@ -30,8 +41,16 @@ func (j *XMLResponse) StatusCode() (code int) {
 
 // WriteTo will write to a given io.Writer
 func (j *XMLResponse) WriteTo(w io.Writer) (n int64, err error) {
+	// Marshal into a local value, responses may be written concurrently by multiple requests
+	bs := j.val
+	if bs == nil && j.v != nil {
+		if bs, err = xml.Marshal(j.v); err != nil {
+			return
+		}
+	}
+
 	var nint int
-	nint, err = w.Write(j.val)
+	nint, err = w.Write(bs)
 	n = int64(nint)
 	return
 }
@@ -0,0 +1,46 @@
+package httpserve
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestXMLValueResponse_concurrent(t *testing.T) {
+	type testUser struct {
+		Name string
+	}
+
+	resp := NewXMLValueResponse(200, testUser{Name: "John Doe"})
+	expected := "<testUser><Name>John Doe</Name></testUser>"
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	values := make(chan string, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := bytes.NewBuffer(nil)
+			if _, err := resp.WriteTo(buf); err != nil {
+				errs <- err
+				return
+			}
+
+			values <- buf.String()
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	close(values)
+	for err := range errs {
+		t.Fatalf("error writing: %v", err)
+	}
+
+	for value := range values {
+		if value != expected {
+			t.Fatalf("invalid value, expected %#v and received %#v", expected, value)
+		}
+	}
+}